@@ -6,35 +6,37 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
 
+	"github.com/yourusername/timeslice-app/internal/config"
 	"github.com/yourusername/timeslice-app/internal/repository"
 )
 
 func main() {
-	// スプレッドシートの設定
+	// スプレッドシートの設定（cmd/main.go と同じ設定ファイル・環境変数を使用）
 	ctx := context.Background()
+	cfg, err := config.Load(os.Args[0], os.Args[1:])
+	if err != nil {
+		log.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
 	wd, err := os.Getwd()
 	if err != nil {
 		log.Fatalf("作業ディレクトリの取得に失敗しました: %v", err)
 	}
 
-	// 認証ファイルへのパスを修正
-	credentialsFile := filepath.Join(wd, "credentials.json")
-	spreadsheetID := "1z1EdC08aVvj0uUfO85HwfIp6k43OmcbPfV91jUrF3EQ"
-
 	fmt.Printf("作業ディレクトリ: %s\n", wd)
-	fmt.Printf("認証ファイル: %s\n", credentialsFile)
+	fmt.Printf("認証ファイル: %s\n", cfg.Sheets.CredentialsFile)
 
 	// スプレッドシートリポジトリの初期化
-	repo, err := repository.NewSheetsRepository(ctx, credentialsFile, spreadsheetID)
+	repo, err := repository.NewSheetsRepository(ctx, cfg.Sheets.CredentialsFile, cfg.Sheets.SpreadsheetID)
 	if err != nil {
 		log.Fatalf("スプレッドシートリポジトリの初期化に失敗しました: %v", err)
 	}
+	repo.DbItemsSheet = cfg.Sheets.DbItemsSheet
+	repo.PresetsSheet = cfg.Sheets.PresetsSheet
 
 	// DB項目の取得
 	fmt.Println("DB項目を取得中...")
-	items, err := repo.GetDbItems()
+	items, err := repo.GetDbItems(ctx)
 	if err != nil {
 		log.Fatalf("DB項目の取得に失敗しました: %v", err)
 	}
@@ -62,7 +64,7 @@ func main() {
 	// タイムエントリの取得
 	date := "2025-04-07" // 確認したい日付
 	fmt.Printf("\n日付 %s のタイムエントリを取得中...\n", date)
-	entries, err := repo.GetTimeEntries(date)
+	entries, err := repo.GetTimeEntries(ctx, date)
 	if err != nil {
 		log.Fatalf("タイムエントリの取得に失敗しました: %v", err)
 	}
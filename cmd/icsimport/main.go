@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/yourusername/timeslice-app/internal/config"
+	"github.com/yourusername/timeslice-app/internal/ics"
+	"github.com/yourusername/timeslice-app/internal/repository"
+	"github.com/yourusername/timeslice-app/internal/timesheet"
+)
+
+func main() {
+	// 取り込み固有の引数（既定は今日の予定）
+	var date, tz, user string
+	var dryRun bool
+	cfg, err := config.Load(os.Args[0], os.Args[1:], func(fs *flag.FlagSet) {
+		fs.StringVar(&date, "date", time.Now().Format("2006-01-02"), "取り込む日（YYYY-MM-DD）")
+		fs.StringVar(&tz, "tz", "", "タイムゾーンの指定がない日時のタイムゾーン（例: Asia/Tokyo、既定はシステムの設定）")
+		fs.BoolVar(&dryRun, "dry-run", false, "保存せずに追加するエントリを表示する")
+		fs.StringVar(&user, "user", "", "取り込み先のユーザー（省略時は認証を導入する前の共通のデータ）")
+	})
+	if err != nil {
+		log.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if len(cfg.Args) != 1 {
+		fmt.Println("使用方法: go run ./cmd/icsimport [-date YYYY-MM-DD] [-tz Asia/Tokyo] [-user ユーザー名] [-dry-run] <.icsファイルのパス>")
+		os.Exit(1)
+	}
+
+	loc := time.Local
+	if tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			log.Fatalf("タイムゾーンが正しくありません: %v", err)
+		}
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		log.Fatalf("日付の形式が正しくありません（YYYY-MM-DD）: %s", date)
+	}
+
+	f, err := os.Open(cfg.Args[0])
+	if err != nil {
+		log.Fatalf("カレンダーファイルの読み込みに失敗しました: %v", err)
+	}
+	events, err := ics.Parse(f, loc)
+	f.Close()
+	if err == nil {
+		events, err = ics.EventsOn(events, day)
+	}
+	if err != nil {
+		log.Fatalf("カレンダーの解釈に失敗しました: %v", err)
+	}
+
+	ctx := repository.WithUser(context.Background(), user)
+	repo, err := repository.NewRepository(ctx, cfg)
+	if err != nil {
+		log.Fatalf("リポジトリの初期化に失敗しました: %v", err)
+	}
+	// 提出済み・承認済みの期間の日には取り込まない
+	if cfg.Timesheets.Enabled {
+		timesheets, err := timesheet.Open(cfg.Timesheets.Path)
+		if err != nil {
+			log.Fatalf("タイムシートの初期化に失敗しました: %v", err)
+		}
+		defer timesheets.Close()
+		repo = timesheet.NewRepository(repo, timesheets)
+	}
+
+	items, err := repo.GetDbItems(ctx)
+	if err != nil {
+		log.Printf("業務データベースの取得に失敗したため対応付けをスキップします: %v", err)
+		items = nil
+	}
+	current, err := repo.GetTimeEntries(ctx, date)
+	if err != nil {
+		log.Fatalf("エントリの取得に失敗しました: %v", err)
+	}
+
+	merged, added := ics.MergeDay(current, events, day, items)
+	jsonData, err := json.MarshalIndent(added, "", "  ")
+	if err != nil {
+		log.Fatalf("JSONへの変換に失敗しました: %v", err)
+	}
+	fmt.Println(string(jsonData))
+	fmt.Printf("%s: 予定 %d 件のうち %d 件を追加します（取り込み済み %d 件）\n", date, len(events), len(added), len(events)-len(added))
+	if dryRun || len(added) == 0 {
+		return
+	}
+
+	if _, err := repo.SaveTimeEntries(ctx, date, merged); err != nil {
+		log.Fatalf("保存に失敗しました: %v", err)
+	}
+	fmt.Println("保存しました")
+}
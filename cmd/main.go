@@ -8,72 +8,242 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/audit"
+	"github.com/yourusername/timeslice-app/internal/auth"
+	"github.com/yourusername/timeslice-app/internal/config"
 	"github.com/yourusername/timeslice-app/internal/handler"
+	"github.com/yourusername/timeslice-app/internal/outbox"
 	"github.com/yourusername/timeslice-app/internal/repository"
+	"github.com/yourusername/timeslice-app/internal/syncer"
+	"github.com/yourusername/timeslice-app/internal/timesheet"
 )
 
 func main() {
-	// スプレッドシートの設定
+	// 設定の読み込み（設定ファイル → 環境変数 → 引数の順に上書き）
+	cfg, err := config.Load(os.Args[0], os.Args[1:])
+	if err != nil {
+		log.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+
 	ctx := context.Background()
 	wd, err := os.Getwd()
 	if err != nil {
 		log.Fatalf("作業ディレクトリの取得に失敗しました: %v", err)
 	}
-	credentialsFile := filepath.Join(wd, "credentials.json")
 
-	// スプレッドシートIDの設定と確認（環境変数から取得することも検討）
-	spreadsheetID := "1z1EdC08aVvj0uUfO85HwfIp6k43OmcbPfV91jUrF3EQ"
-	log.Printf("認証ファイル: %s", credentialsFile)
-	log.Printf("スプレッドシートID: %s", spreadsheetID)
+	// リポジトリの初期化
+	switch cfg.Backend {
+	case config.BackendSQLite:
+		log.Printf("SQLiteデータベース: %s", cfg.SQLite.Path)
+	case config.BackendSheets:
+		log.Printf("認証ファイル: %s", cfg.Sheets.CredentialsFile)
+		log.Printf("スプレッドシートID: %s", cfg.Sheets.SpreadsheetID)
+	}
+	repo, err := repository.NewRepository(ctx, cfg)
+	if err != nil {
+		log.Fatalf("リポジトリの初期化に失敗しました: %v", err)
+	}
+
+	log.Printf("リポジトリの初期化に成功しました (backend=%s)", cfg.Backend)
 
-	// credentials.jsonが存在するか確認
-	if _, err := os.Stat(credentialsFile); os.IsNotExist(err) {
-		log.Fatalf("認証ファイルが見つかりません: %s", credentialsFile)
+	// 送信待ちキュー（sheets バックエンドのみ）
+	// 再送はタイムシートの設定の後に開始する
+	base := repo
+	var queue *outbox.Outbox
+	if cfg.Outbox.Enabled {
+		queue, err = outbox.Open(cfg.Outbox.Path, repo)
+		if err != nil {
+			log.Fatalf("送信待ちキューの初期化に失敗しました: %v", err)
+		}
+		defer queue.Close()
+		repo = outbox.NewQueuedRepository(repo, queue)
+		log.Printf("送信待ちキューを有効にしました: %s", cfg.Outbox.Path)
 	}
 
-	// スプレッドシートリポジトリの初期化
-	repo, err := repository.NewSheetsRepository(ctx, credentialsFile, spreadsheetID)
-	if err != nil {
-		log.Fatalf("スプレッドシートリポジトリの初期化に失敗しました: %v", err)
+	// 同期エンジンは変更日時・同期状態をローカルのデータベースから直接読み書きする
+	// （取り込んだ内容は変更履歴などのラップ後のリポジトリを介して保存する）
+	local, isLocalStore := repo.(syncer.LocalStore)
+
+	// 変更履歴（監査ログ）
+	var auditLog *audit.Log
+	if cfg.Audit.Enabled {
+		auditLog, err = audit.Open(cfg.Audit.Path)
+		if err != nil {
+			log.Fatalf("変更履歴の初期化に失敗しました: %v", err)
+		}
+		defer auditLog.Close()
+		repo = audit.NewRepository(repo, auditLog)
+		log.Printf("変更履歴を有効にしました: %s", cfg.Audit.Path)
+	}
+
+	// タイムシートの提出と承認（提出済みの日の保存は変更履歴に記録する前に拒否する）
+	var timesheets *timesheet.Store
+	if cfg.Timesheets.Enabled {
+		timesheets, err = timesheet.Open(cfg.Timesheets.Path)
+		if err != nil {
+			log.Fatalf("タイムシートの初期化に失敗しました: %v", err)
+		}
+		defer timesheets.Close()
+		repo = timesheet.NewRepository(repo, timesheets)
+		log.Printf("タイムシートの提出と承認を有効にしました: %s", cfg.Timesheets.Path)
+		// キューに登録した後に提出・承認された日は再送しない（送信を中止した項目として残す）
+		if queue != nil {
+			queue.SetTarget(timesheet.NewRepository(base, timesheets))
+		}
+	}
+	if queue != nil {
+		go queue.Run(ctx, cfg.Timeouts.Write)
 	}
 
-	log.Println("スプレッドシートリポジトリの初期化に成功しました")
+	// 認証（アカウント・セッション・APIトークン）
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		store, err := auth.Open(cfg.Auth.Path)
+		if err != nil {
+			log.Fatalf("認証の初期化に失敗しました: %v", err)
+		}
+		defer store.Close()
+		authenticator = auth.NewAuthenticator(store, cfg.Auth.SessionTTL, cfg.Auth.SecureCookie)
+		log.Printf("認証を有効にしました: %s", cfg.Auth.Path)
+		if count, err := store.CountUsers(ctx); err == nil && count == 0 {
+			log.Printf("アカウントが登録されていません。go run ./cmd/user add <ユーザー名> で作成し、go run ./cmd/user role <ユーザー名> admin で管理者にしてください")
+		}
+	} else {
+		log.Printf("認証が無効です。接続できる全員がすべてのデータを読み書きできます")
+	}
 
 	// ハンドラーの初期化
 	h := handler.NewHandler(repo)
+	h.SetTimeouts(handler.Timeouts{
+		Read:  cfg.Timeouts.Read,
+		Write: cfg.Timeouts.Write,
+		Sync:  cfg.Timeouts.Sync,
+	})
+	h.SetCustomFields(cfg.CustomFields)
+	if queue != nil {
+		h.SetOutbox(queue)
+	}
+	if auditLog != nil {
+		h.SetAuditLog(auditLog)
+	}
+	if timesheets != nil {
+		h.SetTimesheets(timesheets)
+	}
+	if authenticator != nil {
+		h.SetAuth(authenticator)
+	}
+
+	// スプレッドシートとの同期（sqlite バックエンドのみ）
+	if cfg.Sync.Enabled {
+		if !isLocalStore {
+			log.Fatalf("同期は sqlite バックエンドでのみ使用できます")
+		}
+		remote, err := repository.NewSheetsRepositoryFromConfig(ctx, cfg)
+		if err != nil {
+			log.Fatalf("同期先のスプレッドシートの初期化に失敗しました: %v", err)
+		}
+		engine := syncer.NewEngine(local, remote)
+		engine.SetLocalWriter(repo)
+		h.SetSyncEngine(engine)
+		log.Printf("スプレッドシートとの同期を有効にしました: %s", cfg.Sheets.SpreadsheetID)
+	}
 
 	// Ginの設定
-	r := gin.Default()
+	if cfg.LogLevel == "debug" {
+		gin.SetMode(gin.DebugMode)
+	} else {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	// アクセスログには calendar.ics の購読URLのAPIトークンを残さない
+	r := gin.New()
+	r.Use(gin.LoggerWithConfig(gin.LoggerConfig{Formatter: auth.LogFormatter}), gin.Recovery())
 
 	// CORSミドルウェアの設定
-	// フロントエンドのオリジンを許可 (ポート番号が異なる場合でもOK)
-	config := cors.DefaultConfig()
-	config.AllowOrigins = []string{"http://localhost:3000", "http://localhost:3001", "http://localhost:3002"} // Add potential frontend ports
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept"}
-	r.Use(cors.New(config))
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOrigins = cfg.CORSOrigins
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match", "If-None-Match", "X-Timeslice-User"}
+	corsConfig.ExposeHeaders = []string{"ETag", "Retry-After"}
+	corsConfig.AllowCredentials = true // フロントエンドからセッション Cookie を送信できるようにする
+	r.Use(cors.New(corsConfig))
 
 	// テンプレートと静的ファイルの設定
 	r.LoadHTMLGlob(filepath.Join(wd, "templates/*"))
 	r.Static("/static", filepath.Join(wd, "static"))
 
+	// ログイン（認証が不要なページとAPI）
+	r.GET(auth.LoginPath, h.ServeLogin)
+	r.POST("/api/auth/login", h.Login)
+	r.POST("/api/auth/logout", h.Logout)
+
+	// 認証が有効な場合、以降のページとAPIはログイン（またはAPIトークン）が必要
+	pages := r.Group("/")
+	api := r.Group("/api")
+	feed := r.Group("/api")
+	if authenticator != nil {
+		pages.Use(authenticator.RequiredForPage())
+		api.Use(authenticator.Required())
+		feed.Use(authenticator.RequiredForFeed())
+	}
+
 	// ルーティング
-	r.GET("/", h.ServeIndex)
-	r.GET("/api/time-entries/:date", h.GetTimeEntries)
-	r.POST("/api/time-entries/:date", h.SaveTimeEntries)
-	r.GET("/api/db-items", h.GetDbItems)
-	r.GET("/api/db-items-v2", h.GetDbItems)
-	r.POST("/api/db-items", h.SaveDbItems)
-	r.DELETE("/api/db-items", h.DeleteDbItems)
+	pages.GET("/", h.ServeIndex)
+	api.GET("/auth/me", h.GetCurrentUser)
+	api.PUT("/auth/password", h.ChangePassword)
+	api.GET("/auth/tokens", h.GetAPITokens)
+	api.POST("/auth/tokens", h.CreateAPIToken)
+	api.DELETE("/auth/tokens/:id", h.DeleteAPIToken)
+	api.GET("/time-entries", h.GetTimeEntriesRange)
+	api.GET("/time-entries/:date", h.GetTimeEntries)
+	api.POST("/time-entries/:date", h.SaveTimeEntries)
+	api.POST("/time-entries/:date/entries", h.CreateTimeEntry)
+	api.POST("/time-entries/:date/entries/:id", h.CreateTimeEntry)
+	api.PATCH("/time-entries/:date/entries/:id", h.UpdateTimeEntry)
+	api.DELETE("/time-entries/:date/entries/:id", h.DeleteTimeEntry)
+	api.POST("/time-entries/:date/reorder", h.ReorderTimeEntries)
+	api.POST("/time-entries/:date/copy-from/:source", h.CopyTimeEntries)
+	api.POST("/time-entries/:date/import/ics", h.ImportICS)
+	api.GET("/db-items", h.GetDbItems)
+	api.GET("/db-items-v2", h.GetDbItems)
+	api.POST("/db-items", h.SaveDbItems)
+	api.DELETE("/db-items", h.DeleteDbItems)
+	api.GET("/presets", h.GetPresets)
+	api.POST("/presets", h.CreatePreset)
+	api.POST("/presets/import", h.ImportPresets)
+	api.PUT("/presets/:id", h.UpdatePreset)
+	api.DELETE("/presets/:id", h.DeletePreset)
+	api.GET("/schema/fields", h.GetFieldSchema)
+	api.GET("/reports/summary", h.GetReportSummary)
+	api.GET("/export", h.ExportTimeEntries)
+	feed.GET("/calendar.ics", h.GetCalendarFeed)
+	api.POST("/sync", h.PostSync)
+	api.GET("/queue", h.GetQueueStatus)
+	api.GET("/history/time-entries/:date", h.GetTimeEntriesHistory)
+	api.POST("/history/time-entries/:date/:id/restore", h.RestoreTimeEntries)
+	api.GET("/history/db-items", h.GetDbItemsHistory)
+	api.GET("/users", h.GetUsers)
+	api.PUT("/users/:user/role", h.SetUserRole)
+	api.GET("/teams", h.GetTeams)
+	api.POST("/teams", h.CreateTeam)
+	api.DELETE("/teams/:team", h.DeleteTeam)
+	api.PUT("/teams/:team/members/:user", h.AddTeamMember)
+	api.DELETE("/teams/:team/members/:user", h.RemoveTeamMember)
+	api.GET("/teams/:team/members/:user/time-entries", h.GetTeamMemberEntries)
+	api.GET("/teams/:team/members/:user/time-entries/:date", h.GetTeamMemberDay)
+	api.GET("/teams/:team/summary", h.GetTeamSummary)
+	api.GET("/timesheets", h.GetTimesheets)
+	api.GET("/timesheets/:period", h.GetTimesheet)
+	api.POST("/timesheets/:period/submit", h.SubmitTimesheet)
+	api.POST("/timesheets/:period/reopen", h.ReopenTimesheet)
+	api.GET("/teams/:team/timesheets", h.GetTeamTimesheets)
+	api.GET("/teams/:team/members/:user/timesheets/:period", h.GetTeamMemberTimesheet)
+	api.POST("/teams/:team/members/:user/timesheets/:period/approve", h.ApproveTimesheet)
+	api.POST("/teams/:team/members/:user/timesheets/:period/reject", h.RejectTimesheet)
+	api.POST("/teams/:team/members/:user/timesheets/:period/reopen", h.ReopenMemberTimesheet)
 
 	// サーバーの起動
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-	addr := "0.0.0.0:" + port
-	log.Printf("サーバーを起動します: http://%s", addr)
-	if err := r.Run(addr); err != nil {
+	log.Printf("サーバーを起動します: http://%s", cfg.ListenAddr)
+	if err := r.Run(cfg.ListenAddr); err != nil {
 		log.Fatalf("サーバーの起動に失敗しました: %v", err)
 	}
 }
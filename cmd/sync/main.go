@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/yourusername/timeslice-app/internal/audit"
+	"github.com/yourusername/timeslice-app/internal/config"
+	"github.com/yourusername/timeslice-app/internal/repository"
+	"github.com/yourusername/timeslice-app/internal/syncer"
+	"github.com/yourusername/timeslice-app/internal/timesheet"
+)
+
+func main() {
+	// 同期固有の引数（既定は直近30日間の双方向同期）
+	today := time.Now().Format("2006-01-02")
+	var from, to, direction, resolve, user string
+	cfg, err := config.Load(os.Args[0], os.Args[1:], func(fs *flag.FlagSet) {
+		fs.StringVar(&from, "from", time.Now().AddDate(0, 0, -30).Format("2006-01-02"), "同期する期間の開始日（YYYY-MM-DD）")
+		fs.StringVar(&to, "to", today, "同期する期間の終了日（YYYY-MM-DD）")
+		fs.StringVar(&direction, "direction", syncer.DirectionBoth, "同期の方向（both, push, pull）")
+		fs.StringVar(&resolve, "resolve", "", "競合時に優先する側（local, remote）。省略時は競合を報告のみ")
+		fs.StringVar(&user, "user", "", "同期するユーザー（省略時は認証を導入する前の共通のデータ）")
+	})
+	if err != nil {
+		log.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if err := cfg.ValidateSync(); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := repository.WithUser(context.Background(), user)
+	local, err := repository.NewSQLiteRepository(cfg.SQLite.Path)
+	if err != nil {
+		log.Fatalf("SQLiteリポジトリの初期化に失敗しました: %v", err)
+	}
+	defer local.Close()
+
+	remote, err := repository.NewSheetsRepositoryFromConfig(ctx, cfg)
+	if err != nil {
+		log.Fatalf("スプレッドシートリポジトリの初期化に失敗しました: %v", err)
+	}
+
+	engine := syncer.NewEngine(local, remote)
+
+	// 取り込んだ内容は変更履歴に同期として記録する
+	var writer repository.Repository = local
+	if cfg.Audit.Enabled {
+		auditLog, err := audit.Open(cfg.Audit.Path)
+		if err != nil {
+			log.Fatalf("変更履歴の初期化に失敗しました: %v", err)
+		}
+		defer auditLog.Close()
+		writer = audit.NewRepository(writer, auditLog)
+		ctx = audit.WithAction(audit.WithActor(ctx, "sync"), audit.ActionSync)
+	}
+	// 提出済み・承認済みの日は取り込まない
+	if cfg.Timesheets.Enabled {
+		timesheets, err := timesheet.Open(cfg.Timesheets.Path)
+		if err != nil {
+			log.Fatalf("タイムシートの初期化に失敗しました: %v", err)
+		}
+		defer timesheets.Close()
+		writer = timesheet.NewRepository(writer, timesheets)
+	}
+	engine.SetLocalWriter(writer)
+
+	fmt.Printf("同期を開始します: %s〜%s (direction=%s)\n", from, to, direction)
+	result, err := engine.Sync(ctx, syncer.Options{
+		From:      from,
+		To:        to,
+		Direction: direction,
+		Resolve:   resolve,
+	})
+	if err != nil {
+		log.Fatalf("同期に失敗しました: %v", err)
+	}
+
+	fmt.Printf("送信: %v\n", result.Pushed)
+	fmt.Printf("取り込み: %v\n", result.Pulled)
+	fmt.Printf("変更なし: %d日\n", result.Unchanged)
+	if len(result.Locked) > 0 {
+		fmt.Printf("提出済みのため取り込まなかった日: %v\n", result.Locked)
+	}
+	if len(result.Conflicts) > 0 {
+		fmt.Printf("競合: %d日（-resolve local または -resolve remote で解決できます）\n", len(result.Conflicts))
+		jsonData, err := json.MarshalIndent(result.Conflicts, "", "  ")
+		if err != nil {
+			log.Fatalf("JSONへの変換に失敗しました: %v", err)
+		}
+		fmt.Println(string(jsonData))
+		os.Exit(2)
+	}
+}
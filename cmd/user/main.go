@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/yourusername/timeslice-app/internal/auth"
+	"github.com/yourusername/timeslice-app/internal/config"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+const usage = `使用方法: go run ./cmd/user [-config config.yaml] <コマンド> [ユーザー名]
+
+コマンド:
+  list              アカウントと権限の一覧を表示する
+  add <ユーザー名>    アカウントを作成する（パスワードは標準入力から読み込みます。権限は member）
+  passwd <ユーザー名> パスワードを変更する（ログイン中のセッションは無効になります）
+  role <ユーザー名> <権限>  権限を member, manager, admin のいずれかに変更する
+  delete <ユーザー名> アカウントとAPIトークンを削除する（エントリは削除しません）
+  claim <ユーザー名>  認証を導入する前の共通のエントリをそのユーザーのエントリにする（sqlite のみ）`
+
+func main() {
+	cfg, err := config.Load(os.Args[0], os.Args[1:])
+	if err != nil {
+		log.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if len(cfg.Args) == 0 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+	command, args := cfg.Args[0], cfg.Args[1:]
+	argCount := map[string]int{"list": 0, "role": 2}
+	want, ok := argCount[command]
+	if !ok {
+		want = 1
+	}
+	if len(args) != want {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	store, err := auth.Open(cfg.Auth.Path)
+	if err != nil {
+		log.Fatalf("アカウントのデータベースを開けません: %v", err)
+	}
+	defer store.Close()
+
+	switch command {
+	case "list":
+		users, err := store.ListUsers(ctx)
+		if err != nil {
+			log.Fatalf("アカウントの取得に失敗しました: %v", err)
+		}
+		for _, user := range users {
+			fmt.Printf("%s\t%s\t%s\n", user.Username, user.Role, user.CreatedAt)
+		}
+	case "add":
+		if err := auth.ValidateUsername(args[0]); err != nil {
+			log.Fatal(err)
+		}
+		if err := store.CreateUser(ctx, args[0], readPassword()); err != nil {
+			log.Fatalf("アカウントの作成に失敗しました: %v", err)
+		}
+		fmt.Printf("アカウントを作成しました: %s\n", args[0])
+	case "passwd":
+		if err := store.SetPassword(ctx, args[0], readPassword()); err != nil {
+			log.Fatalf("パスワードの変更に失敗しました: %v", err)
+		}
+		fmt.Printf("パスワードを変更しました: %s\n", args[0])
+	case "role":
+		role, err := auth.ParseRole(args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := store.SetRole(ctx, args[0], role); err != nil {
+			log.Fatalf("権限の変更に失敗しました: %v", err)
+		}
+		fmt.Printf("権限を変更しました: %s (%s)\n", args[0], role)
+	case "claim":
+		exists, err := store.UserExists(ctx, args[0])
+		if err != nil {
+			log.Fatalf("アカウントの取得に失敗しました: %v", err)
+		}
+		if !exists {
+			log.Fatalf("アカウントが登録されていません: %s", args[0])
+		}
+		claim(ctx, cfg, args[0])
+	case "delete":
+		if err := store.DeleteUser(ctx, args[0]); err != nil {
+			log.Fatalf("アカウントの削除に失敗しました: %v", err)
+		}
+		fmt.Printf("アカウントを削除しました: %s\n", args[0])
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+}
+
+// readPassword は標準入力からパスワードを1行読み込みます
+func readPassword() string {
+	fmt.Fprint(os.Stderr, "パスワード: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		log.Fatalf("パスワードを読み込めません: %v", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if err := auth.ValidatePassword(password); err != nil {
+		log.Fatal(err)
+	}
+	return password
+}
+
+// claim は共通のエントリを user のエントリにします
+func claim(ctx context.Context, cfg *config.Config, user string) {
+	if cfg.Backend != config.BackendSQLite {
+		log.Fatalf("claim は sqlite バックエンドでのみ使用できます。スプレッドシートでは日付シートの名前を %s/YYYY-MM-DD に変更してください", user)
+	}
+	repo, err := repository.NewSQLiteRepository(cfg.SQLite.Path)
+	if err != nil {
+		log.Fatalf("SQLiteリポジトリの初期化に失敗しました: %v", err)
+	}
+	defer repo.Close()
+
+	claimed, err := repo.ClaimSharedEntries(ctx, user)
+	if err != nil {
+		log.Fatalf("エントリの移行に失敗しました: %v", err)
+	}
+	fmt.Printf("%d 件のエントリを %s のエントリにしました\n", claimed, user)
+}
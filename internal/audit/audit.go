@@ -0,0 +1,232 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// 記録の対象
+const (
+	KindTimeEntries = "time_entries" // 日付ごとのタイムエントリ
+	KindDbItems     = "db_items"     // 業務データベース
+)
+
+// 操作の種類
+const (
+	ActionSave    = "save"
+	ActionDelete  = "delete"
+	ActionRestore = "restore" // 過去の版に戻した保存
+	ActionSync    = "sync"    // スプレッドシートからの取り込み
+)
+
+// DefaultLimit は一覧で返す件数の既定値です
+const DefaultLimit = 50
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Record は1回の保存・削除の記録です
+// Before / After は変更前後の内容（タイムエントリまたは業務データベースの項目の配列）です
+type Record struct {
+	ID        int64           `json:"id"`
+	Kind      string          `json:"kind"`
+	Date      string          `json:"date,omitempty"`
+	User      string          `json:"user,omitempty"` // タイムエントリの持ち主（認証が無効の場合は空）
+	Action    string          `json:"action"`
+	Actor     string          `json:"actor"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// Log は変更履歴をSQLiteに追記します
+// 記録の更新・削除はトリガーで禁止しており、追記のみが可能です
+type Log struct {
+	db *sql.DB
+}
+
+// Open は変更履歴のデータベースを開きます（存在しない場合は作成します）
+func Open(path string) (*Log, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind TEXT NOT NULL,
+			date TEXT NOT NULL DEFAULT '',
+			action TEXT NOT NULL,
+			actor TEXT NOT NULL DEFAULT '',
+			before TEXT NOT NULL,
+			after TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_audit_log_kind_date ON audit_log (kind, date, id);
+
+		CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+		BEGIN
+			SELECT RAISE(ABORT, 'audit_log is append-only');
+		END;
+
+		CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+		BEGIN
+			SELECT RAISE(ABORT, 'audit_log is append-only');
+		END;
+	`)
+	if err == nil {
+		err = addUserColumn(db)
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("変更履歴の作成に失敗しました: %w", err)
+	}
+
+	return &Log{db: db}, nil
+}
+
+// addUserColumn はユーザーの列がない（認証の導入前に作成された）変更履歴に列を追加します
+func addUserColumn(db *sql.DB) error {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('audit_log') WHERE name = 'user_id'`).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = db.Exec(`
+		ALTER TABLE audit_log ADD COLUMN user_id TEXT NOT NULL DEFAULT '';
+
+		CREATE INDEX IF NOT EXISTS idx_audit_log_kind_user_date ON audit_log (kind, user_id, date, id);
+	`)
+	return err
+}
+
+// Close は変更履歴のデータベースを閉じます
+func (l *Log) Close() error {
+	return l.db.Close()
+}
+
+// Append は変更を1件記録します。操作者とタイムエントリのユーザーは ctx から取得します
+func (l *Log) Append(ctx context.Context, kind, date, action string, before, after interface{}) (int64, error) {
+	beforeData, err := json.Marshal(before)
+	if err != nil {
+		return 0, err
+	}
+	afterData, err := json.Marshal(after)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := l.db.ExecContext(ctx, `
+		INSERT INTO audit_log (kind, date, user_id, action, actor, before, after, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, kind, date, recordUser(ctx, kind), action, ActorFromContext(ctx), string(beforeData), string(afterData), time.Now().Format(timeLayout))
+	if err != nil {
+		return 0, fmt.Errorf("変更履歴の記録に失敗しました: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// List は kind（タイムエントリの場合は date と ctx のユーザーも）の記録を新しい順に最大 limit 件返します
+func (l *Log) List(ctx context.Context, kind, date string, limit int) ([]Record, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	rows, err := l.db.QueryContext(ctx, `
+		SELECT id, kind, date, user_id, action, actor, before, after, created_at
+		FROM audit_log
+		WHERE kind = ? AND user_id = ? AND date = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`, kind, recordUser(ctx, kind), date, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []Record{}
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+	return records, rows.Err()
+}
+
+// Get は id の記録を返します。存在しない場合は nil を返します
+func (l *Log) Get(ctx context.Context, id int64) (*Record, error) {
+	row := l.db.QueryRowContext(ctx, `
+		SELECT id, kind, date, user_id, action, actor, before, after, created_at
+		FROM audit_log
+		WHERE id = ?
+	`, id)
+	record, err := scanRecord(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return record, err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRecord(s scanner) (*Record, error) {
+	var record Record
+	var before, after string
+	err := s.Scan(&record.ID, &record.Kind, &record.Date, &record.User, &record.Action, &record.Actor, &before, &after, &record.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	record.Before = json.RawMessage(before)
+	record.After = json.RawMessage(after)
+	return &record, nil
+}
+
+// recordUser は記録のユーザーを返します。業務データベースは全員で共有するためユーザーを記録しません
+func recordUser(ctx context.Context, kind string) string {
+	if kind != KindTimeEntries {
+		return ""
+	}
+	return repository.UserFromContext(ctx)
+}
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	actionKey
+)
+
+// WithActor は記録する操作者を ctx に設定します
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromContext は ctx に設定された操作者を返します
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// WithAction は記録する操作の種類を ctx に設定します（省略時は保存・削除から判断します）
+func WithAction(ctx context.Context, action string) context.Context {
+	return context.WithValue(ctx, actionKey, action)
+}
+
+// actionFromContext は ctx に設定された操作の種類を返します。設定されていない場合は def を返します
+func actionFromContext(ctx context.Context, def string) string {
+	if action, ok := ctx.Value(actionKey).(string); ok && action != "" {
+		return action
+	}
+	return def
+}
@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// Repository は保存・削除の前後の内容を変更履歴に記録するリポジトリです
+// 記録に失敗しても保存自体は成功として扱い、ログに出力します
+type Repository struct {
+	repository.Repository
+	log *Log
+}
+
+func NewRepository(repo repository.Repository, l *Log) *Repository {
+	return &Repository{Repository: repo, log: l}
+}
+
+// SaveTimeEntries は保存前の内容を取得してから保存し、内容が変わった場合に記録します
+// 送信待ちキューに登録された場合も、保存を受け付けた時点で記録します
+func (r *Repository) SaveTimeEntries(ctx context.Context, date string, entries []models.TimeEntry) (time.Time, error) {
+	before, err := r.Repository.GetTimeEntries(ctx, date)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	updatedAt, err := r.Repository.SaveTimeEntries(ctx, date, entries)
+	if err != nil && !errors.As(err, new(*repository.QueuedError)) {
+		return updatedAt, err
+	}
+
+	if models.EntriesHash(before) != models.EntriesHash(entries) {
+		action := ActionSave
+		if len(entries) == 0 {
+			action = ActionDelete
+		}
+		r.append(ctx, KindTimeEntries, date, actionFromContext(ctx, action), nonNilEntries(before), nonNilEntries(entries))
+	}
+	return updatedAt, err
+}
+
+// SaveDbItems は業務データベースを置き換え、置き換え前後の項目を記録します
+func (r *Repository) SaveDbItems(ctx context.Context, items []models.DbItem) error {
+	before, err := r.Repository.GetDbItems(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.SaveDbItems(ctx, items); err != nil {
+		return err
+	}
+
+	r.append(ctx, KindDbItems, "", actionFromContext(ctx, ActionSave), nonNilItems(before), nonNilItems(items))
+	return nil
+}
+
+// DeleteDbItems は業務データベースから項目を削除し、削除前後の項目を記録します
+func (r *Repository) DeleteDbItems(ctx context.Context, items []models.DbItem) error {
+	before, err := r.Repository.GetDbItems(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.DeleteDbItems(ctx, items); err != nil {
+		return err
+	}
+
+	deleted := make(map[models.DbItem]bool, len(items))
+	for _, item := range items {
+		deleted[models.DbItem{Type: models.NormalizeFieldKey(item.Type), Value: item.Value}] = true
+	}
+	after := []models.DbItem{}
+	for _, item := range before {
+		if !deleted[models.DbItem{Type: models.NormalizeFieldKey(item.Type), Value: item.Value}] {
+			after = append(after, item)
+		}
+	}
+	r.append(ctx, KindDbItems, "", actionFromContext(ctx, ActionDelete), nonNilItems(before), nonNilItems(after))
+	return nil
+}
+
+func (r *Repository) append(ctx context.Context, kind, date, action string, before, after interface{}) {
+	// 保存が完了した後の記録は、リクエストの取り消しや期限切れで中断しない
+	if _, err := r.log.Append(context.WithoutCancel(ctx), kind, date, action, before, after); err != nil {
+		log.Printf("変更履歴: %v", err)
+	}
+}
+
+// nonNilEntries は JSON で null ではなく [] として記録するために nil を空のスライスにします
+func nonNilEntries(entries []models.TimeEntry) []models.TimeEntry {
+	if entries == nil {
+		return []models.TimeEntry{}
+	}
+	return entries
+}
+
+func nonNilItems(items []models.DbItem) []models.DbItem {
+	if items == nil {
+		return []models.DbItem{}
+	}
+	return items
+}
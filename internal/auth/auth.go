@@ -0,0 +1,135 @@
+// Package auth はローカルのアカウント（ユーザー名とパスワード）によるログイン、
+// セッション Cookie と個人用のAPIトークンによる認証、権限とチームの管理を提供します
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// エラー
+var (
+	ErrInvalidCredentials = errors.New("ユーザー名またはパスワードが正しくありません")
+	ErrUserExists         = errors.New("ユーザーは既に登録されています")
+	ErrUserNotFound       = errors.New("ユーザーが見つかりません")
+	ErrTokenNotFound      = errors.New("APIトークンが見つかりません")
+	ErrTeamExists         = errors.New("チームは既に登録されています")
+	ErrTeamNotFound       = errors.New("チームが見つかりません")
+	ErrNotTeamMember      = errors.New("チームのメンバーではありません")
+)
+
+// Role はアカウントの権限です
+type Role string
+
+// 権限
+const (
+	RoleMember  Role = "member"  // 自分のエントリのみ
+	RoleManager Role = "manager" // 所属するチームのメンバーのエントリを閲覧できる（編集はできない）
+	RoleAdmin   Role = "admin"   // すべてのチームの閲覧、業務データベース・アカウント・チームの管理
+)
+
+// roleRanks は権限の強さです
+var roleRanks = map[Role]int{RoleMember: 0, RoleManager: 1, RoleAdmin: 2}
+
+// ParseRole は権限の名前を検証して返します
+func ParseRole(s string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := roleRanks[role]; !ok {
+		return "", fmt.Errorf("権限は member, manager, admin のいずれかを指定してください: %q", s)
+	}
+	return role, nil
+}
+
+// AtLeast は r が required 以上の権限かどうかを返します
+func (r Role) AtLeast(required Role) bool {
+	rank, ok := roleRanks[r]
+	return ok && rank >= roleRanks[required]
+}
+
+// maxTeamNameLength はチーム名の最大の文字数です
+const maxTeamNameLength = 64
+
+// MinPasswordLength はパスワードの最小の文字数です
+const MinPasswordLength = 8
+
+// tokenPrefix はAPIトークンの接頭辞です（漏えいしたトークンを見つけやすくするため）
+const tokenPrefix = "ts_"
+
+// usernamePattern はユーザー名に使用できる文字です
+// ユーザー名はスプレッドシートのシート名（ユーザー/日付）にも使用するため、英小文字・数字・._- に限ります
+var usernamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,31}$`)
+
+// User はアカウントです
+type User struct {
+	Username  string `json:"username"`
+	Role      Role   `json:"role"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Team はマネージャーが閲覧できる範囲を表すメンバーの集まりです
+type Team struct {
+	Name      string   `json:"name"`
+	Members   []string `json:"members"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// Token は個人用のAPIトークンです。トークンの値はハッシュのみを保存し、作成時にのみ返します
+type Token struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+}
+
+// ValidateUsername はユーザー名が使用できる形式かどうかを検証します
+func ValidateUsername(username string) error {
+	if !usernamePattern.MatchString(username) {
+		return fmt.Errorf("ユーザー名は英小文字・数字・._- の32文字以内で、英小文字か数字で始めてください: %q", username)
+	}
+	return nil
+}
+
+// ValidateTeamName はチーム名を検証し、前後の空白を除いて返します
+func ValidateTeamName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("チーム名を指定してください")
+	}
+	if utf8.RuneCountInString(name) > maxTeamNameLength {
+		return "", fmt.Errorf("チーム名は%d文字以内にしてください", maxTeamNameLength)
+	}
+	return name, nil
+}
+
+// ValidatePassword はパスワードの長さを検証します（bcrypt は72バイトまでを使用します）
+func ValidatePassword(password string) error {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return fmt.Errorf("パスワードは%d文字以上にしてください", MinPasswordLength)
+	}
+	if len(password) > 72 {
+		return fmt.Errorf("パスワードは72バイト以内にしてください")
+	}
+	return nil
+}
+
+// newSecret はセッションやAPIトークンの値（推測できない乱数）を作成します
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashSecret はセッションやAPIトークンの値を保存用のハッシュに変換します
+// 値は十分に長い乱数のため、パスワードと異なり低速なハッシュは使用しません
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CookieName はログインのセッションを保存する Cookie の名前です
+const CookieName = "timeslice_session"
+
+// userKey と roleKey はログインしたユーザー名と権限を gin.Context に保存するキーです
+const (
+	userKey = "timeslice.user"
+	roleKey = "timeslice.role"
+)
+
+// LoginPath はログイン画面のパスです
+const LoginPath = "/login"
+
+// Authenticator はリクエストのユーザーをセッション Cookie またはAPIトークンから特定します
+type Authenticator struct {
+	Store        *Store
+	SessionTTL   time.Duration
+	SecureCookie bool // Cookie に Secure 属性を付ける（HTTPS の場合）
+}
+
+// NewAuthenticator は認証を作成します
+func NewAuthenticator(store *Store, sessionTTL time.Duration, secureCookie bool) *Authenticator {
+	return &Authenticator{Store: store, SessionTTL: sessionTTL, SecureCookie: secureCookie}
+}
+
+// Required はログインしていないリクエストを 401 で拒否するミドルウェアです（API用）
+// Authorization: Bearer <APIトークン> またはセッション Cookie で認証します
+func (a *Authenticator) Required() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.authenticate(c, false) {
+			c.Next()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "ログインしてください"})
+	}
+}
+
+// RequiredForFeed は Required に加えて、クエリの token でのAPIトークンの指定を受け付けます
+// ヘッダーを設定できないカレンダーアプリの購読（calendar.ics）に使用します
+func (a *Authenticator) RequiredForFeed() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.authenticate(c, true) {
+			c.Next()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "ログインしてください"})
+	}
+}
+
+// LogFormatter はクエリの token を伏せてアクセスログを出力する gin のログの書式です
+// calendar.ics の購読URLに含まれるAPIトークンをログに残さないために使用します（書式は gin の既定と同じ）
+func LogFormatter(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		redactToken(param.Path),
+		param.ErrorMessage,
+	)
+}
+
+// redactToken はパスのクエリの token の値を伏せます（他のパラメータと順序はそのまま残します）
+func redactToken(path string) string {
+	base, query, found := strings.Cut(path, "?")
+	if !found {
+		return path
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil && name == "token" {
+			params[i] = key + "=REDACTED"
+		}
+	}
+	return base + "?" + strings.Join(params, "&")
+}
+
+// RequiredForPage はログインしていない場合にログイン画面へ移動させるミドルウェアです（画面用）
+func (a *Authenticator) RequiredForPage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.authenticate(c, false) {
+			c.Next()
+			return
+		}
+		c.Redirect(http.StatusFound, LoginPath+"?next="+url.QueryEscape(c.Request.URL.RequestURI()))
+		c.Abort()
+	}
+}
+
+// authenticate はリクエストのユーザーを特定して gin.Context に保存します
+func (a *Authenticator) authenticate(c *gin.Context, allowQueryToken bool) bool {
+	ctx := c.Request.Context()
+
+	secret, isToken := bearerToken(c)
+	if !isToken && allowQueryToken {
+		secret = c.Query("token")
+		isToken = secret != ""
+	}
+
+	var user *User
+	var err error
+	if isToken {
+		user, err = a.Store.TokenUser(ctx, secret)
+	} else if cookie, cerr := c.Cookie(CookieName); cerr == nil && cookie != "" {
+		user, err = a.Store.SessionUser(ctx, cookie)
+	}
+	if err != nil {
+		log.Printf("認証情報の確認に失敗しました: %v", err)
+		return false
+	}
+	if user == nil {
+		return false
+	}
+	c.Set(userKey, user.Username)
+	c.Set(roleKey, user.Role)
+	return true
+}
+
+// bearerToken は Authorization ヘッダーのトークンを返します
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// SetSessionCookie はログインのセッションを Cookie に保存します
+func (a *Authenticator) SetSessionCookie(c *gin.Context, secret string, expires time.Time) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     CookieName,
+		Value:    secret,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   a.SecureCookie,
+		SameSite: http.SameSiteLaxMode, // 他のサイトからの POST などでは送信しない
+	})
+}
+
+// ClearSessionCookie はセッションの Cookie を削除します
+func (a *Authenticator) ClearSessionCookie(c *gin.Context) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     CookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   a.SecureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// UserName はミドルウェアが特定したユーザー名を返します（認証が無効の場合は空文字）
+func UserName(c *gin.Context) string {
+	return c.GetString(userKey)
+}
+
+// UserRole はミドルウェアが特定したユーザーの権限を返します（認証が無効の場合は空文字）
+func UserRole(c *gin.Context) Role {
+	role, _ := c.Get(roleKey)
+	r, _ := role.(Role)
+	return r
+}
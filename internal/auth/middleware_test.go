@@ -0,0 +1,20 @@
+package auth
+
+import "testing"
+
+func TestRedactToken(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{in: "/api/calendar.ics", want: "/api/calendar.ics"},
+		{in: "/api/calendar.ics?token=secret", want: "/api/calendar.ics?token=REDACTED"},
+		{in: "/api/calendar.ics?from=2026-10-01&token=secret&to=2026-10-31", want: "/api/calendar.ics?from=2026-10-01&token=REDACTED&to=2026-10-31"},
+		{in: "/api/calendar.ics?%74oken=secret", want: "/api/calendar.ics?%74oken=REDACTED"},
+		{in: "/api/calendar.ics?tokens=1", want: "/api/calendar.ics?tokens=1"},
+	}
+	for _, tt := range tests {
+		if got := redactToken(tt.in); got != tt.want {
+			t.Errorf("redactToken(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
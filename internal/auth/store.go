@@ -0,0 +1,367 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// dummyHash はユーザーが存在しない場合にも照合の時間を揃えるためのハッシュです
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("timeslice-dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// Store はアカウント・セッション・APIトークンをSQLiteに保存します
+type Store struct {
+	db *sql.DB
+}
+
+// Open はアカウントのデータベースを開きます（存在しない場合は作成します）
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS users (
+			username TEXT PRIMARY KEY,
+			password_hash TEXT NOT NULL,
+			role TEXT NOT NULL DEFAULT 'member',
+			created_at TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS sessions (
+			token_hash TEXT PRIMARY KEY,
+			username TEXT NOT NULL,
+			expires_at TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS api_tokens (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			username TEXT NOT NULL,
+			name TEXT NOT NULL,
+			token_hash TEXT NOT NULL UNIQUE,
+			created_at TEXT NOT NULL,
+			last_used_at TEXT NOT NULL DEFAULT ''
+		);
+
+		CREATE TABLE IF NOT EXISTS teams (
+			name TEXT PRIMARY KEY,
+			created_at TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS team_members (
+			team TEXT NOT NULL,
+			username TEXT NOT NULL,
+			PRIMARY KEY (team, username)
+		);
+	`)
+	if err == nil {
+		err = addRoleColumn(db)
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("アカウントのデータベースの作成に失敗しました: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// addRoleColumn は権限の列がない（権限の導入前に作成された）データベースに列を追加します
+func addRoleColumn(db *sql.DB) error {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('users') WHERE name = 'role'`).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = db.Exec(`ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'member'`)
+	return err
+}
+
+// Close はデータベースを閉じます
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// CountUsers は登録されているアカウントの数を返します
+func (s *Store) CountUsers(ctx context.Context) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&count)
+	return count, err
+}
+
+// UserExists はアカウントが登録されているかどうかを返します
+func (s *Store) UserExists(ctx context.Context, username string) (bool, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE username = ?`, username).Scan(&count)
+	return count > 0, err
+}
+
+// ListUsers はアカウントをユーザー名の順に返します
+func (s *Store) ListUsers(ctx context.Context) ([]User, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT username, role, created_at FROM users ORDER BY username`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.Username, &user.Role, &user.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// CreateUser はアカウントを作成します（権限は member）
+func (s *Store) CreateUser(ctx context.Context, username, password string) error {
+	if err := ValidateUsername(username); err != nil {
+		return err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO users (username, password_hash, created_at) VALUES (?, ?, ?)
+	`, username, hash, time.Now().Format(timeLayout))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE") {
+		return ErrUserExists
+	}
+	return err
+}
+
+// SetPassword はパスワードを変更し、そのユーザーのログイン中のセッションをすべて無効にします
+func (s *Store) SetPassword(ctx context.Context, username, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `UPDATE users SET password_hash = ? WHERE username = ?`, hash, username)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		tx.Rollback()
+		return ErrUserNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM sessions WHERE username = ?`, username); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetRole はアカウントの権限を変更します
+func (s *Store) SetRole(ctx context.Context, username string, role Role) error {
+	res, err := s.db.ExecContext(ctx, `UPDATE users SET role = ? WHERE username = ?`, string(role), username)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// DeleteUser はアカウントと、そのセッション・APIトークン・チームの所属を削除します（エントリは削除しません）
+func (s *Store) DeleteUser(ctx context.Context, username string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM users WHERE username = ?`, username)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		tx.Rollback()
+		return ErrUserNotFound
+	}
+	for _, table := range []string{"sessions", "api_tokens", "team_members"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE username = ?", username); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Authenticate はユーザー名とパスワードを照合します
+func (s *Store) Authenticate(ctx context.Context, username, password string) error {
+	var hash string
+	err := s.db.QueryRowContext(ctx, `SELECT password_hash FROM users WHERE username = ?`, username).Scan(&hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		// ユーザーの有無が応答時間からわからないよう、存在しない場合も照合する
+		bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return ErrInvalidCredentials
+	}
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// CreateSession はログインのセッションを作成し、Cookie に保存する値と有効期限を返します
+// 期限切れのセッションはこのときに削除します
+func (s *Store) CreateSession(ctx context.Context, username string, ttl time.Duration) (string, time.Time, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	now := time.Now()
+	expires := now.Add(ttl)
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at < ?`, now.Format(timeLayout)); err != nil {
+		return "", time.Time{}, err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO sessions (token_hash, username, expires_at, created_at) VALUES (?, ?, ?, ?)
+	`, hashSecret(secret), username, expires.Format(timeLayout), now.Format(timeLayout))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return secret, expires, nil
+}
+
+// SessionUser は有効なセッションのアカウントを返します（無効な場合は nil）
+func (s *Store) SessionUser(ctx context.Context, secret string) (*User, error) {
+	var user User
+	err := s.db.QueryRowContext(ctx, `
+		SELECT u.username, u.role, u.created_at
+		FROM sessions s JOIN users u ON u.username = s.username
+		WHERE s.token_hash = ? AND s.expires_at >= ?
+	`, hashSecret(secret), time.Now().Format(timeLayout)).Scan(&user.Username, &user.Role, &user.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteSession はセッションを削除します（ログアウト）
+func (s *Store) DeleteSession(ctx context.Context, secret string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE token_hash = ?`, hashSecret(secret))
+	return err
+}
+
+// CreateToken はAPIトークンを作成し、トークンの値を返します。値は再表示できません
+func (s *Store) CreateToken(ctx context.Context, username, name string) (*Token, string, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	secret = tokenPrefix + secret
+	now := time.Now().Format(timeLayout)
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO api_tokens (username, name, token_hash, created_at) VALUES (?, ?, ?, ?)
+	`, username, name, hashSecret(secret), now)
+	if err != nil {
+		return nil, "", err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, "", err
+	}
+	return &Token{ID: id, Name: name, CreatedAt: now}, secret, nil
+}
+
+// ListTokens はユーザーのAPIトークンを作成順に返します
+func (s *Store) ListTokens(ctx context.Context, username string) ([]Token, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, name, created_at, last_used_at FROM api_tokens WHERE username = ? ORDER BY id
+	`, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []Token{}
+	for rows.Next() {
+		var token Token
+		if err := rows.Scan(&token.ID, &token.Name, &token.CreatedAt, &token.LastUsedAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+// DeleteToken はユーザーのAPIトークンを削除します
+func (s *Store) DeleteToken(ctx context.Context, username string, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM api_tokens WHERE id = ? AND username = ?`, id, username)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrTokenNotFound
+	}
+	return nil
+}
+
+// TokenUser はAPIトークンのアカウントを返し、最終使用日時を記録します（無効な場合は nil）
+func (s *Store) TokenUser(ctx context.Context, secret string) (*User, error) {
+	if !strings.HasPrefix(secret, tokenPrefix) {
+		return nil, nil
+	}
+	hash := hashSecret(secret)
+	var user User
+	err := s.db.QueryRowContext(ctx, `
+		SELECT u.username, u.role, u.created_at
+		FROM api_tokens t JOIN users u ON u.username = t.username
+		WHERE t.token_hash = ?
+	`, hash).Scan(&user.Username, &user.Role, &user.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.db.ExecContext(ctx, `UPDATE api_tokens SET last_used_at = ? WHERE token_hash = ?`,
+		time.Now().Format(timeLayout), hash)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func hashPassword(password string) (string, error) {
+	if err := ValidatePassword(password); err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
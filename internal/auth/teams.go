@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ListTeams はすべてのチームをチーム名の順に返します
+func (s *Store) ListTeams(ctx context.Context) ([]Team, error) {
+	return s.queryTeams(ctx, ``)
+}
+
+// UserTeams は username が所属するチームを返します
+func (s *Store) UserTeams(ctx context.Context, username string) ([]Team, error) {
+	return s.queryTeams(ctx, `WHERE t.name IN (SELECT team FROM team_members WHERE username = ?)`, username)
+}
+
+// GetTeam はチームとそのメンバーを返します
+func (s *Store) GetTeam(ctx context.Context, name string) (*Team, error) {
+	teams, err := s.queryTeams(ctx, `WHERE t.name = ?`, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(teams) == 0 {
+		return nil, ErrTeamNotFound
+	}
+	return &teams[0], nil
+}
+
+// queryTeams は条件に一致するチームをメンバーとともに返します
+func (s *Store) queryTeams(ctx context.Context, where string, args ...any) ([]Team, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT t.name, t.created_at, COALESCE(m.username, '')
+		FROM teams t LEFT JOIN team_members m ON m.team = t.name
+		`+where+`
+		ORDER BY t.name, m.username
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []Team{}
+	for rows.Next() {
+		var name, createdAt, member string
+		if err := rows.Scan(&name, &createdAt, &member); err != nil {
+			return nil, err
+		}
+		if len(teams) == 0 || teams[len(teams)-1].Name != name {
+			teams = append(teams, Team{Name: name, Members: []string{}, CreatedAt: createdAt})
+		}
+		if member != "" {
+			team := &teams[len(teams)-1]
+			team.Members = append(team.Members, member)
+		}
+	}
+	return teams, rows.Err()
+}
+
+// CreateTeam はメンバーのいないチームを作成します
+func (s *Store) CreateTeam(ctx context.Context, name string) (*Team, error) {
+	name, err := ValidateTeamName(name)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Format(timeLayout)
+	_, err = s.db.ExecContext(ctx, `INSERT INTO teams (name, created_at) VALUES (?, ?)`, name, now)
+	if err != nil && strings.Contains(err.Error(), "UNIQUE") {
+		return nil, ErrTeamExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Team{Name: name, Members: []string{}, CreatedAt: now}, nil
+}
+
+// DeleteTeam はチームとその所属を削除します（メンバーのアカウントは削除しません）
+func (s *Store) DeleteTeam(ctx context.Context, name string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM teams WHERE name = ?`, name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		tx.Rollback()
+		return ErrTeamNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM team_members WHERE team = ?`, name); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddTeamMember は username をチームに追加します（既に所属している場合は何もしません）
+func (s *Store) AddTeamMember(ctx context.Context, team, username string) error {
+	var teams, users int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT (SELECT COUNT(*) FROM teams WHERE name = ?), (SELECT COUNT(*) FROM users WHERE username = ?)
+	`, team, username).Scan(&teams, &users)
+	if err != nil {
+		return err
+	}
+	if teams == 0 {
+		return ErrTeamNotFound
+	}
+	if users == 0 {
+		return ErrUserNotFound
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT OR IGNORE INTO team_members (team, username) VALUES (?, ?)`, team, username)
+	return err
+}
+
+// RemoveTeamMember は username をチームから外します
+func (s *Store) RemoveTeamMember(ctx context.Context, team, username string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM team_members WHERE team = ? AND username = ?`, team, username)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotTeamMember
+	}
+	return nil
+}
@@ -0,0 +1,406 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// バックエンドの種類
+const (
+	BackendSheets = "sheets"
+	BackendSQLite = "sqlite"
+)
+
+// DefaultFile は -config や TIMESLICE_CONFIG が指定されていない場合に読み込む設定ファイルです
+const DefaultFile = "config.yaml"
+
+// Config はアプリケーション全体の設定を表します
+type Config struct {
+	Backend     string           `yaml:"backend"`      // sheets または sqlite
+	ListenAddr  string           `yaml:"listen_addr"`  // 例: 0.0.0.0:8080
+	LogLevel    string           `yaml:"log_level"`    // debug, info, warn, error
+	CORSOrigins []string         `yaml:"cors_origins"` // 許可するフロントエンドのオリジン
+	Sheets      SheetsConfig     `yaml:"sheets"`
+	SQLite      SQLiteConfig     `yaml:"sqlite"`
+	Sync        SyncConfig       `yaml:"sync"`
+	Outbox      OutboxConfig     `yaml:"outbox"`
+	Audit       AuditConfig      `yaml:"audit"`
+	Auth        AuthConfig       `yaml:"auth"`
+	Timesheets  TimesheetsConfig `yaml:"timesheets"`
+	Timeouts    Timeouts         `yaml:"timeouts"`
+
+	// CustomFields はタイムエントリに追加する項目です（スプレッドシートではID列の後に並びます）
+	CustomFields []models.CustomField `yaml:"custom_fields"`
+
+	// Args はコマンドライン引数のうちフラグとして解釈されなかった残りの引数です
+	Args []string `yaml:"-"`
+}
+
+// SheetsConfig はGoogleスプレッドシート連携の設定です
+type SheetsConfig struct {
+	SpreadsheetID   string      `yaml:"spreadsheet_id"`
+	CredentialsFile string      `yaml:"credentials_file"`
+	DbItemsSheet    string      `yaml:"db_items_sheet"` // 業務データベースのシート名
+	PresetsSheet    string      `yaml:"presets_sheet"`  // プリセットのシート名
+	Retry           RetryConfig `yaml:"retry"`
+}
+
+// RetryConfig はSheets APIのレート制限や一時的なエラーに対する再試行の設定です
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"` // 最初の呼び出しを含む最大試行回数
+	MaxElapsed  time.Duration `yaml:"max_elapsed"`  // 再試行を含めた合計時間の上限（例: 30s）
+}
+
+// SQLiteConfig はSQLiteの設定です
+type SQLiteConfig struct {
+	Path string `yaml:"path"`
+}
+
+// SyncConfig はSQLiteとスプレッドシートの同期の設定です
+type SyncConfig struct {
+	// Enabled が true の場合、sqlite バックエンドで POST /api/sync を有効にします
+	Enabled bool `yaml:"enabled"`
+}
+
+// OutboxConfig はスプレッドシートへの保存が失敗した場合の送信待ちキューの設定です
+type OutboxConfig struct {
+	// Enabled が true の場合、sheets バックエンドで保存に失敗した日をキューに登録して後で再送します
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"` // キューを保存するSQLiteファイル
+}
+
+// AuditConfig はタイムエントリと業務データベースの変更履歴（監査ログ）の設定です
+type AuditConfig struct {
+	// Enabled が true の場合、保存・削除のたびに変更前後の内容を記録します
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"` // 変更履歴を保存するSQLiteファイル
+}
+
+// AuthConfig はログインとユーザーごとのデータの分離の設定です
+type AuthConfig struct {
+	// Enabled が true の場合、API の利用にログイン（またはAPIトークン）を必要とし、エントリをユーザーごとに保存します
+	Enabled      bool          `yaml:"enabled"`
+	Path         string        `yaml:"path"`          // アカウント・セッション・APIトークンを保存するSQLiteファイル
+	SessionTTL   time.Duration `yaml:"session_ttl"`   // ログインの有効期間（例: 168h）
+	SecureCookie bool          `yaml:"secure_cookie"` // HTTPS で公開する場合に true（Cookie に Secure 属性を付ける）
+}
+
+// TimesheetsConfig は週・月単位のタイムシートの提出と承認の設定です
+type TimesheetsConfig struct {
+	// Enabled が true の場合、タイムシートの提出・承認を有効にし、提出済み・承認済みの期間の日の保存を禁止します
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"` // タイムシートの状態と遷移の記録を保存するSQLiteファイル
+}
+
+// Timeouts は操作の種類ごとの処理時間の上限です（0の場合は上限なし）
+type Timeouts struct {
+	Read  time.Duration `yaml:"read"`  // 取得・集計
+	Write time.Duration `yaml:"write"` // 保存・削除
+	Sync  time.Duration `yaml:"sync"`  // スプレッドシートとの同期
+}
+
+// Default は既定値の設定を返します
+func Default() *Config {
+	return &Config{
+		Backend:     BackendSheets,
+		ListenAddr:  "0.0.0.0:8080",
+		LogLevel:    "info",
+		CORSOrigins: []string{"http://localhost:3000", "http://localhost:3001", "http://localhost:3002"},
+		Sheets: SheetsConfig{
+			CredentialsFile: "credentials.json",
+			DbItemsSheet:    "業務データベース",
+			PresetsSheet:    "プリセット",
+			Retry: RetryConfig{
+				MaxAttempts: 5,
+				MaxElapsed:  30 * time.Second,
+			},
+		},
+		SQLite: SQLiteConfig{
+			Path: "timeslice.db",
+		},
+		Outbox: OutboxConfig{
+			Path: "outbox.db",
+		},
+		Audit: AuditConfig{
+			Enabled: true,
+			Path:    "audit.db",
+		},
+		Auth: AuthConfig{
+			Enabled:    false, // 既存のデータはユーザーに紐付いていないため、移行してから有効にする
+			Path:       "auth.db",
+			SessionTTL: 7 * 24 * time.Hour,
+		},
+		Timesheets: TimesheetsConfig{
+			Enabled: false,
+			Path:    "timesheets.db",
+		},
+		Timeouts: Timeouts{
+			Read:  20 * time.Second,
+			Write: 60 * time.Second,
+			Sync:  5 * time.Minute,
+		},
+	}
+}
+
+// Load は 既定値 → 設定ファイル → 環境変数 → コマンドライン引数 の順に設定を読み込み、検証します
+// extra にはコマンド固有のフラグを登録する関数を指定できます
+func Load(name string, args []string, extra ...func(fs *flag.FlagSet)) (*Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := fs.String("config", "", "設定ファイルのパス（YAML）")
+	backend := fs.String("backend", "", "ストレージバックエンド（sheets, sqlite）")
+	listenAddr := fs.String("addr", "", "待ち受けアドレス（例: 0.0.0.0:8080）")
+	logLevel := fs.String("log-level", "", "ログレベル（debug, info, warn, error）")
+	spreadsheetID := fs.String("spreadsheet-id", "", "スプレッドシートID")
+	credentialsFile := fs.String("credentials", "", "Google認証情報ファイルのパス")
+	sqlitePath := fs.String("sqlite-path", "", "SQLiteデータベースファイルのパス")
+	for _, register := range extra {
+		register(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	cfg.Args = fs.Args()
+
+	// 設定ファイル
+	path := *configFile
+	if path == "" {
+		path = os.Getenv("TIMESLICE_CONFIG")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	} else if _, err := os.Stat(DefaultFile); err == nil {
+		if err := cfg.loadFile(DefaultFile); err != nil {
+			return nil, err
+		}
+	}
+
+	// 環境変数
+	cfg.applyEnv()
+
+	// コマンドライン引数
+	setIfNotEmpty(&cfg.Backend, *backend)
+	setIfNotEmpty(&cfg.ListenAddr, *listenAddr)
+	setIfNotEmpty(&cfg.LogLevel, *logLevel)
+	setIfNotEmpty(&cfg.Sheets.SpreadsheetID, *spreadsheetID)
+	setIfNotEmpty(&cfg.Sheets.CredentialsFile, *credentialsFile)
+	setIfNotEmpty(&cfg.SQLite.Path, *sqlitePath)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("設定ファイル %s の解析に失敗しました: %v", path, err)
+	}
+	return nil
+}
+
+func (c *Config) applyEnv() {
+	setIfNotEmpty(&c.Backend, os.Getenv("TIMESLICE_BACKEND"))
+	// 従来の PORT 環境変数も引き続きサポートする
+	if port := os.Getenv("PORT"); port != "" {
+		c.ListenAddr = "0.0.0.0:" + port
+	}
+	setIfNotEmpty(&c.ListenAddr, os.Getenv("TIMESLICE_LISTEN_ADDR"))
+	setIfNotEmpty(&c.LogLevel, os.Getenv("TIMESLICE_LOG_LEVEL"))
+	if origins := os.Getenv("TIMESLICE_CORS_ORIGINS"); origins != "" {
+		c.CORSOrigins = splitList(origins)
+	}
+	setIfNotEmpty(&c.Sheets.SpreadsheetID, os.Getenv("TIMESLICE_SPREADSHEET_ID"))
+	setIfNotEmpty(&c.Sheets.CredentialsFile, os.Getenv("TIMESLICE_CREDENTIALS_FILE"))
+	setIfNotEmpty(&c.Sheets.DbItemsSheet, os.Getenv("TIMESLICE_DB_ITEMS_SHEET"))
+	setIfNotEmpty(&c.Sheets.PresetsSheet, os.Getenv("TIMESLICE_PRESETS_SHEET"))
+	setIfNotEmpty(&c.SQLite.Path, os.Getenv("TIMESLICE_SQLITE_PATH"))
+	if enabled, err := strconv.ParseBool(os.Getenv("TIMESLICE_SYNC_ENABLED")); err == nil {
+		c.Sync.Enabled = enabled
+	}
+	if enabled, err := strconv.ParseBool(os.Getenv("TIMESLICE_OUTBOX_ENABLED")); err == nil {
+		c.Outbox.Enabled = enabled
+	}
+	setIfNotEmpty(&c.Outbox.Path, os.Getenv("TIMESLICE_OUTBOX_PATH"))
+	if enabled, err := strconv.ParseBool(os.Getenv("TIMESLICE_AUDIT_ENABLED")); err == nil {
+		c.Audit.Enabled = enabled
+	}
+	setIfNotEmpty(&c.Audit.Path, os.Getenv("TIMESLICE_AUDIT_PATH"))
+	if enabled, err := strconv.ParseBool(os.Getenv("TIMESLICE_AUTH_ENABLED")); err == nil {
+		c.Auth.Enabled = enabled
+	}
+	setIfNotEmpty(&c.Auth.Path, os.Getenv("TIMESLICE_AUTH_PATH"))
+	if secure, err := strconv.ParseBool(os.Getenv("TIMESLICE_AUTH_SECURE_COOKIE")); err == nil {
+		c.Auth.SecureCookie = secure
+	}
+	if enabled, err := strconv.ParseBool(os.Getenv("TIMESLICE_TIMESHEETS_ENABLED")); err == nil {
+		c.Timesheets.Enabled = enabled
+	}
+	setIfNotEmpty(&c.Timesheets.Path, os.Getenv("TIMESLICE_TIMESHEETS_PATH"))
+}
+
+// Validate は設定値を検証し、問題があればすべてまとめてエラーとして返します
+func (c *Config) Validate() error {
+	var problems []string
+
+	switch c.Backend {
+	case BackendSheets:
+		problems = append(problems, c.validateSheets()...)
+	case BackendSQLite:
+		problems = append(problems, c.validateSQLite()...)
+		// 同期にはスプレッドシートの設定も必要
+		if c.Sync.Enabled {
+			problems = append(problems, c.validateSheets()...)
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("backend の値が不正です: %q（sheets または sqlite）", c.Backend))
+	}
+
+	if c.Sync.Enabled && c.Backend != BackendSQLite {
+		problems = append(problems, "sync.enabled は backend が sqlite の場合のみ指定できます")
+	}
+
+	if c.Outbox.Enabled {
+		if c.Backend != BackendSheets {
+			problems = append(problems, "outbox.enabled は backend が sheets の場合のみ指定できます")
+		}
+		if c.Outbox.Path == "" {
+			problems = append(problems, "outbox.path が設定されていません")
+		}
+	}
+
+	keys := make(map[string]bool)
+	for _, field := range c.CustomFields {
+		if err := field.Check(); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if keys[field.Key] {
+			problems = append(problems, fmt.Sprintf("カスタム項目のキー %q が重複しています", field.Key))
+		}
+		keys[field.Key] = true
+	}
+
+	if c.Audit.Enabled && c.Audit.Path == "" {
+		problems = append(problems, "audit.path が設定されていません")
+	}
+
+	if c.Auth.Enabled {
+		if c.Auth.Path == "" {
+			problems = append(problems, "auth.path が設定されていません")
+		}
+		if c.Auth.SessionTTL <= 0 {
+			problems = append(problems, "auth.session_ttl は0より大きい値を指定してください")
+		}
+	}
+
+	if c.Timesheets.Enabled && c.Timesheets.Path == "" {
+		problems = append(problems, "timesheets.path が設定されていません")
+	}
+
+	if c.Timeouts.Read < 0 || c.Timeouts.Write < 0 || c.Timeouts.Sync < 0 {
+		problems = append(problems, "timeouts には0以上の値を指定してください")
+	}
+
+	if c.ListenAddr == "" {
+		problems = append(problems, "listen_addr が設定されていません")
+	}
+
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, fmt.Sprintf("log_level の値が不正です: %q（debug, info, warn, error）", c.LogLevel))
+	}
+
+	for _, origin := range c.CORSOrigins {
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("cors_origins の値が不正です: %q", origin))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("設定に誤りがあります:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// ValidateSync は同期コマンドの実行に必要な設定（SQLiteとスプレッドシートの両方）を検証します
+func (c *Config) ValidateSync() error {
+	problems := append(c.validateSQLite(), c.validateSheets()...)
+	if len(problems) > 0 {
+		return fmt.Errorf("同期の設定に誤りがあります:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+func (c *Config) validateSheets() []string {
+	var problems []string
+	if c.Sheets.SpreadsheetID == "" {
+		problems = append(problems, "sheets.spreadsheet_id が設定されていません（TIMESLICE_SPREADSHEET_ID または -spreadsheet-id でも指定できます）")
+	}
+	if c.Sheets.CredentialsFile == "" {
+		problems = append(problems, "sheets.credentials_file が設定されていません")
+	} else if _, err := os.Stat(c.Sheets.CredentialsFile); err != nil {
+		problems = append(problems, fmt.Sprintf("認証ファイルが見つかりません: %s", c.Sheets.CredentialsFile))
+	}
+	if c.Sheets.DbItemsSheet == "" {
+		problems = append(problems, "sheets.db_items_sheet が設定されていません")
+	}
+	if c.Sheets.PresetsSheet == "" {
+		problems = append(problems, "sheets.presets_sheet が設定されていません")
+	} else if c.Sheets.PresetsSheet == c.Sheets.DbItemsSheet {
+		problems = append(problems, "sheets.presets_sheet には業務データベースと別のシートを指定してください")
+	}
+	if c.Sheets.Retry.MaxAttempts < 1 {
+		problems = append(problems, "sheets.retry.max_attempts は1以上を指定してください")
+	}
+	if c.Sheets.Retry.MaxElapsed <= 0 {
+		problems = append(problems, "sheets.retry.max_elapsed は0より大きい値を指定してください")
+	}
+	return problems
+}
+
+func (c *Config) validateSQLite() []string {
+	if c.SQLite.Path == "" {
+		return []string{"sqlite.path が設定されていません"}
+	}
+	return nil
+}
+
+func setIfNotEmpty(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
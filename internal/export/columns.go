@@ -0,0 +1,110 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// 項目以外に出力できる列のキー
+const (
+	ColumnDate    = "date"    // 日付（YYYY-MM-DD）
+	ColumnMinutes = "minutes" // 時間を解釈した所要時間（分）
+	ColumnID      = "id"      // エントリのID
+)
+
+// Column は出力する1つの列です
+type Column struct {
+	Key    string
+	Header string
+	value  func(date string, entry models.TimeEntry) interface{}
+}
+
+// dateColumn などは項目以外の列の定義です
+var (
+	dateColumn = Column{Key: ColumnDate, Header: "日付", value: func(date string, _ models.TimeEntry) interface{} {
+		return date
+	}}
+	minutesColumn = Column{Key: ColumnMinutes, Header: "所要時間（分）", value: func(_ string, entry models.TimeEntry) interface{} {
+		slot, err := entry.Slot()
+		if err != nil {
+			return nil // 解釈できない時間は空欄にする
+		}
+		return slot.Minutes
+	}}
+	idColumn = Column{Key: ColumnID, Header: "ID", value: func(_ string, entry models.TimeEntry) interface{} {
+		return entry.ID
+	}}
+)
+
+func fieldColumn(f models.Field) Column {
+	return Column{Key: f.Key, Header: f.Label, value: func(_ string, entry models.TimeEntry) interface{} {
+		return f.Value(entry)
+	}}
+}
+
+func customColumn(f models.CustomField) Column {
+	return Column{Key: f.Key, Header: f.DisplayLabel(), value: func(_ string, entry models.TimeEntry) interface{} {
+		return entry.Custom[f.Key]
+	}}
+}
+
+// DefaultColumns は既定の列（日付と日付シートと同じ項目、カスタム項目）を返します
+func DefaultColumns(customFields []models.CustomField) []Column {
+	columns := []Column{dateColumn}
+	for _, f := range models.Fields {
+		columns = append(columns, fieldColumn(f))
+	}
+	for _, f := range customFields {
+		columns = append(columns, customColumn(f))
+	}
+	return columns
+}
+
+// ParseColumns はカンマ区切りの列の指定を解釈します（未指定の場合は DefaultColumns）
+// 列はキー・見出し・別名のいずれでも指定でき、指定した順に出力します
+func ParseColumns(s string, customFields []models.CustomField) ([]Column, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultColumns(customFields), nil
+	}
+
+	candidates := []Column{dateColumn, minutesColumn, idColumn}
+	var columns []Column
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		name := strings.TrimSpace(part)
+		if name == "" {
+			continue
+		}
+
+		var column Column
+		found := false
+		if f, ok := models.LookupField(name); ok {
+			column, found = fieldColumn(f), true
+		}
+		for _, c := range candidates {
+			if !found && (strings.EqualFold(name, c.Key) || name == c.Header) {
+				column, found = c, true
+			}
+		}
+		for _, f := range customFields {
+			if !found && f.Matches(name) {
+				column, found = customColumn(f), true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("出力できない列です: %s", name)
+		}
+
+		if seen[column.Key] {
+			continue
+		}
+		seen[column.Key] = true
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return DefaultColumns(customFields), nil
+	}
+	return columns, nil
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// Export は from〜to（両端を含む）のエントリを日付順に w へ書き込み、書き込んだエントリの件数を返します
+// 先頭の行は列の見出しです。エントリは1か月ずつ取得して書き込むため、長い期間でも一度に読み込みません
+// 最初の取得に失敗した場合は w に何も書き込まずにエラーを返します
+func Export(ctx context.Context, repo repository.Repository, from, to string, columns []Column, w Writer) (int, error) {
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return 0, err
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	header := make([]interface{}, len(columns))
+	for i, c := range columns {
+		header[i] = c.Header
+	}
+
+	for chunk := start; !chunk.After(end); {
+		// 月末または to までを1回で取得する
+		chunkEnd := time.Date(chunk.Year(), chunk.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+		entriesByDate, err := repo.GetTimeEntriesRange(ctx, chunk.Format("2006-01-02"), chunkEnd.Format("2006-01-02"))
+		if err != nil {
+			return count, err
+		}
+
+		if header != nil {
+			if err := w.WriteRow(header); err != nil {
+				return count, err
+			}
+			header = nil
+		}
+
+		dates := make([]string, 0, len(entriesByDate))
+		for date := range entriesByDate {
+			dates = append(dates, date)
+		}
+		sort.Strings(dates)
+		for _, date := range dates {
+			for _, entry := range entriesByDate[date] {
+				row := make([]interface{}, len(columns))
+				for i, c := range columns {
+					row[i] = c.value(date, entry)
+				}
+				if err := w.WriteRow(row); err != nil {
+					return count, err
+				}
+				count++
+			}
+		}
+
+		chunk = chunkEnd.AddDate(0, 0, 1)
+	}
+	return count, nil
+}
@@ -0,0 +1,205 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// 出力形式
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// utf8BOM はExcelがUTF-8のCSVとして開くために先頭に付けるバイト列です
+const utf8BOM = "\xEF\xBB\xBF"
+
+// Writer は行を順に書き込む出力先です
+// 最初の WriteRow を呼び出すまでは w に何も書き込みません
+type Writer interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// NewWriter は形式に応じた Writer を作成します。bom は CSV の場合のみ使用します
+func NewWriter(format string, w io.Writer, bom bool) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: w, bom: bom}, nil
+	case FormatXLSX:
+		return &xlsxWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("未対応の出力形式です: %s（csv または xlsx）", format)
+	}
+}
+
+// ContentType は形式の Content-Type を返します
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// formatCell はセルの値を文字列に変換します
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// csvWriter はCSVで書き込みます
+type csvWriter struct {
+	w   io.Writer
+	bom bool
+	csv *csv.Writer
+}
+
+func (c *csvWriter) WriteRow(values []interface{}) error {
+	if c.csv == nil {
+		if c.bom {
+			if _, err := io.WriteString(c.w, utf8BOM); err != nil {
+				return err
+			}
+		}
+		c.csv = csv.NewWriter(c.w)
+		c.csv.UseCRLF = true // Excel の既定に合わせる
+	}
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = formatCell(v)
+	}
+	return c.csv.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	if c.csv == nil {
+		return nil
+	}
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// xlsx の固定の部品（ワークシートは1枚のみ）
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="タイムエントリ" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxWriter はExcelのブック（.xlsx）で書き込みます
+// ワークシートは行ごとにzipへ書き出すため、行数によらずメモリ使用量は一定です
+type xlsxWriter struct {
+	w     io.Writer
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+}
+
+// start は固定の部品を書き込み、ワークシートの書き込みを開始します
+func (x *xlsxWriter) start() error {
+	x.zip = zip.NewWriter(x.w)
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, p := range parts {
+		f, err := x.zip.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, p.body); err != nil {
+			return err
+		}
+	}
+	f, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(f)
+	_, err = x.sheet.WriteString(xlsxSheetStart)
+	return err
+}
+
+func (x *xlsxWriter) WriteRow(values []interface{}) error {
+	if x.zip == nil {
+		if err := x.start(); err != nil {
+			return err
+		}
+	}
+	x.rows++
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, x.rows)
+	for i, value := range values {
+		ref := repository.ColumnName(i) + strconv.Itoa(x.rows)
+		switch v := value.(type) {
+		case nil:
+			continue
+		case int:
+			fmt.Fprintf(&b, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case float64:
+			fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			n := 0
+			if v {
+				n = 1
+			}
+			fmt.Fprintf(&b, `<c r="%s" t="b"><v>%d</v></c>`, ref, n)
+		default:
+			s := formatCell(v)
+			if s == "" {
+				continue
+			}
+			fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			xml.EscapeText(&b, []byte(s))
+			b.WriteString(`</t></is></c>`)
+		}
+	}
+	b.WriteString(`</row>`)
+	_, err := x.sheet.WriteString(b.String())
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	if x.zip == nil {
+		// 行がない場合も空のブックを出力する
+		if err := x.start(); err != nil {
+			return err
+		}
+	}
+	if _, err := x.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
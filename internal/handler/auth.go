@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/auth"
+)
+
+// SetAuth はログインとAPIトークンの管理を有効にします
+func (h *Handler) SetAuth(a *auth.Authenticator) {
+	h.auth = a
+}
+
+// authEnabled は認証が無効な場合にエラーレスポンスを書き込み、false を返します
+func (h *Handler) authEnabled(c *gin.Context) bool {
+	if h.auth == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "認証が設定されていません（auth.enabled: true が必要です）"})
+		return false
+	}
+	return true
+}
+
+// ServeLogin はログイン画面を表示します
+func (h *Handler) ServeLogin(c *gin.Context) {
+	c.HTML(http.StatusOK, "login.html", nil)
+}
+
+// Login はユーザー名とパスワードを照合し、セッション Cookie を設定します
+func (h *Handler) Login(c *gin.Context) {
+	if !h.authEnabled(c) {
+		return
+	}
+	var req struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	username := strings.ToLower(strings.TrimSpace(req.Username))
+
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	if err := h.auth.Store.Authenticate(ctx, username, req.Password); err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		respondError(c, err)
+		return
+	}
+	secret, expires, err := h.auth.Store.CreateSession(ctx, username, h.auth.SessionTTL)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.auth.SetSessionCookie(c, secret, expires)
+	c.JSON(http.StatusOK, gin.H{"user": username, "expires_at": expires.Format("2006/01/02 15:04:05")})
+}
+
+// Logout はセッションを無効にし、Cookie を削除します
+func (h *Handler) Logout(c *gin.Context) {
+	if !h.authEnabled(c) {
+		return
+	}
+	if secret, err := c.Cookie(auth.CookieName); err == nil && secret != "" {
+		ctx, cancel := requestContext(c, h.timeouts.Write)
+		defer cancel()
+		if err := h.auth.Store.DeleteSession(ctx, secret); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+	h.auth.ClearSessionCookie(c)
+	c.JSON(http.StatusOK, gin.H{"message": "ログアウトしました"})
+}
+
+// GetCurrentUser はログインしているユーザーと権限を返します
+func (h *Handler) GetCurrentUser(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"auth_enabled": h.auth != nil, "user": auth.UserName(c), "role": auth.UserRole(c)})
+}
+
+// ChangePassword はログインしているユーザーのパスワードを変更します
+// 他の端末のログインは無効になり、このリクエストの端末には新しいセッションを設定します
+func (h *Handler) ChangePassword(c *gin.Context) {
+	if !h.authEnabled(c) {
+		return
+	}
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := auth.ValidatePassword(req.NewPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user := auth.UserName(c)
+
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	if err := h.auth.Store.Authenticate(ctx, user, req.CurrentPassword); err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "現在のパスワードが正しくありません"})
+			return
+		}
+		respondError(c, err)
+		return
+	}
+	if err := h.auth.Store.SetPassword(ctx, user, req.NewPassword); err != nil {
+		respondError(c, err)
+		return
+	}
+	secret, expires, err := h.auth.Store.CreateSession(ctx, user, h.auth.SessionTTL)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.auth.SetSessionCookie(c, secret, expires)
+	c.JSON(http.StatusOK, gin.H{"message": "パスワードを変更しました"})
+}
+
+// GetAPITokens はログインしているユーザーのAPIトークンの一覧を返します（トークンの値は含みません）
+func (h *Handler) GetAPITokens(c *gin.Context) {
+	if !h.authEnabled(c) {
+		return
+	}
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	tokens, err := h.auth.Store.ListTokens(ctx, auth.UserName(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// CreateAPIToken はAPIトークンを作成します。トークンの値はこのレスポンスでのみ返します
+func (h *Handler) CreateAPIToken(c *gin.Context) {
+	if !h.authEnabled(c) {
+		return
+	}
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "トークンの名前（name）を指定してください"})
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	token, secret, err := h.auth.Store.CreateToken(ctx, auth.UserName(c), name)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"token": secret, "info": token})
+}
+
+// DeleteAPIToken はログインしているユーザーのAPIトークンを削除します
+func (h *Handler) DeleteAPIToken(c *gin.Context) {
+	if !h.authEnabled(c) {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "トークンのIDが正しくありません"})
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	if err := h.auth.Store.DeleteToken(ctx, auth.UserName(c), id); err != nil {
+		if errors.Is(err, auth.ErrTokenNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "削除しました"})
+}
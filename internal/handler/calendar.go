@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/ics"
+)
+
+// GetCalendarFeed は期間内のエントリをカレンダー（.ics）として返します。カレンダーアプリの購読に使用します
+// tz でエントリの時刻のタイムゾーンを、start で時間帯のないエントリを並べ始める時刻（既定は 09:00）を指定できます
+func (h *Handler) GetCalendarFeed(c *gin.Context) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	loc, ok := locationParam(c)
+	if !ok {
+		return
+	}
+	dayStart := ics.DefaultDayStart
+	if s := c.Query("start"); s != "" {
+		t, err := time.Parse("15:04", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start は HH:MM の形式で指定してください"})
+			return
+		}
+		dayStart = t.Hour()*60 + t.Minute()
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Sync)
+	defer cancel()
+	entries, err := h.repo.GetTimeEntriesRange(ctx, from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="timeslice_%s_%s.ics"`, from, to))
+	c.Status(http.StatusOK)
+	err = ics.WriteFeed(c.Writer, entries, ics.FeedOptions{
+		Name:         "TimeSlice",
+		Location:     loc,
+		DayStart:     dayStart,
+		CustomFields: h.validator.CustomFields,
+	})
+	if err != nil {
+		fmt.Printf("カレンダーの書き出しに失敗しました (%s〜%s): %v\n", from, to, err)
+	}
+}
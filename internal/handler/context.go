@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/audit"
+	"github.com/yourusername/timeslice-app/internal/auth"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// actorHeader は変更履歴に記録する操作者名を指定するヘッダーです（認証が無効の場合のみ使用します）
+const actorHeader = "X-Timeslice-User"
+
+// Timeouts は操作の種類ごとの処理時間の上限です（0以下の場合は上限なし）
+type Timeouts struct {
+	Read  time.Duration // 取得・集計
+	Write time.Duration // 保存・削除
+	Sync  time.Duration // スプレッドシートとの同期
+}
+
+// DefaultTimeouts は既定の処理時間の上限を返します
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Read:  20 * time.Second,
+		Write: 60 * time.Second,
+		Sync:  5 * time.Minute,
+	}
+}
+
+// SetTimeouts は処理時間の上限を変更します
+func (h *Handler) SetTimeouts(timeouts Timeouts) {
+	h.timeouts = timeouts
+}
+
+// requestContext はリクエストのコンテキストに処理時間の上限・操作者・データのユーザーを設定したものを返します
+// ブラウザが接続を切った場合もリポジトリの処理が中断されます
+func requestContext(c *gin.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx := audit.WithActor(c.Request.Context(), requestActor(c))
+	ctx = repository.WithUser(ctx, auth.UserName(c))
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// requestActor は変更履歴に記録する操作者を返します
+// ログインしている場合はそのユーザー、認証が無効の場合はヘッダーの値（ない場合は接続元のIPアドレス）です
+func requestActor(c *gin.Context) string {
+	if user := auth.UserName(c); user != "" {
+		return user
+	}
+	if actor := c.GetHeader(actorHeader); actor != "" {
+		return actor
+	}
+	return c.ClientIP()
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// previousSource はコピー元に指定できる「コピー先の前日」を表します
+const previousSource = "previous"
+
+// copyOptions は他の日からエントリをコピーする際のオプションです
+type copyOptions struct {
+	KeepTimes    *bool `json:"keep_times"`    // 時間帯（09:00 - 09:30）をそのまま残す（既定）。false の場合は所要時間（分）に変換する
+	ClearRemarks bool  `json:"clear_remarks"` // 備考を空にする
+	SkipWeekends bool  `json:"skip_weekends"` // コピー元が土日の場合は直前の金曜日からコピーする
+	Replace      bool  `json:"replace"`       // コピー先のエントリを置き換える（既定は末尾に追加）
+}
+
+// copySourceDate はコピー元の日付を決定します
+func copySourceDate(date, source string, skipWeekends bool) (string, error) {
+	var day time.Time
+	if source == previousSource {
+		target, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return "", err
+		}
+		day = target.AddDate(0, 0, -1)
+	} else {
+		if !isValidDate(source) {
+			return "", fmt.Errorf("コピー元の日付の形式が正しくありません（YYYY-MM-DD または %s）", previousSource)
+		}
+		day, _ = time.Parse("2006-01-02", source)
+	}
+	if skipWeekends {
+		for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			day = day.AddDate(0, 0, -1)
+		}
+	}
+	return day.Format("2006-01-02"), nil
+}
+
+// copyEntries はコピー元のエントリからオプションに従って新しいIDのエントリを作成します
+func copyEntries(source []models.TimeEntry, opts copyOptions) []models.TimeEntry {
+	keepTimes := opts.KeepTimes == nil || *opts.KeepTimes
+	copied := make([]models.TimeEntry, len(source))
+	for i, entry := range source {
+		entry.ID = models.NewEntryID()
+		if !keepTimes {
+			if slot, err := entry.Slot(); err == nil && slot.Minutes > 0 {
+				entry.Time = fmt.Sprintf("%d", slot.Minutes)
+			}
+		}
+		if opts.ClearRemarks {
+			entry.Remark = ""
+		}
+		copied[i] = entry
+	}
+	return copied
+}
+
+// CopyTimeEntries は他の日（:source、previous の場合は前日）のエントリを :date にコピーします
+// 本文のJSONでオプション（keep_times, clear_remarks, skip_weekends, replace）を指定できます
+func (h *Handler) CopyTimeEntries(c *gin.Context) {
+	date, ok := dateParam(c)
+	if !ok {
+		return
+	}
+
+	var opts copyOptions
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&opts); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	source, err := copySourceDate(date, c.Param("source"), opts.SkipWeekends)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if source == date {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "コピー元とコピー先が同じ日です"})
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	sourceEntries, err := h.repo.GetTimeEntries(ctx, source)
+	cancel()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if len(sourceEntries) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("コピー元の日（%s）にエントリがありません", source)})
+		return
+	}
+	copied := copyEntries(sourceEntries, opts)
+
+	// 合計時間などの日全体の検証を行い、新しい日には曜日のテンプレートを先頭に追加する
+	h.changeDay(c, date, http.StatusOK, func(entries []models.TimeEntry) ([]models.TimeEntry, int, error) {
+		var result []models.TimeEntry
+		switch {
+		case len(entries) == 0:
+			result = h.newDayTemplates(c, date, copied)
+		case !opts.Replace:
+			result = entries
+		}
+		return append(result, copied...), changedDay, nil
+	}, func(entries []models.TimeEntry, changed int) gin.H {
+		return gin.H{
+			"source":  source,
+			"copied":  len(copied),
+			"entries": toFrontendEntries(entries),
+		}
+	})
+}
@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/auth"
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/validation"
+)
+
+// createEntryRequest はエントリの追加リクエストです
+type createEntryRequest struct {
+	models.TimeEntry
+	Position *int `json:"position"` // 挿入する位置（0始まり、省略時は末尾）
+}
+
+// entryPatch はエントリの部分更新です。指定された項目のみを変更します
+type entryPatch struct {
+	Time    *string `json:"time"`
+	Content *string `json:"content"`
+	Client  *string `json:"client"`
+	Purpose *string `json:"purpose"`
+	Action  *string `json:"action"`
+	With    *string `json:"with"`
+	PcCc    *string `json:"pccc"`
+	Remark  *string `json:"remark"`
+
+	// Custom は指定されたカスタム項目のみを変更します（null の項目は削除します）
+	Custom map[string]interface{} `json:"custom"`
+}
+
+func (p entryPatch) apply(entry *models.TimeEntry) {
+	fields := []struct {
+		value *string
+		dest  *string
+	}{
+		{p.Time, &entry.Time},
+		{p.Content, &entry.Content},
+		{p.Client, &entry.Client},
+		{p.Purpose, &entry.Purpose},
+		{p.Action, &entry.Action},
+		{p.With, &entry.With},
+		{p.PcCc, &entry.PcCc},
+		{p.Remark, &entry.Remark},
+	}
+	for _, f := range fields {
+		if f.value != nil {
+			*f.dest = *f.value
+		}
+	}
+
+	if len(p.Custom) > 0 {
+		custom := make(map[string]interface{}, len(entry.Custom)+len(p.Custom))
+		for key, value := range entry.Custom {
+			custom[key] = value
+		}
+		for key, value := range p.Custom {
+			if value == nil {
+				delete(custom, key)
+			} else {
+				custom[key] = value
+			}
+		}
+		entry.Custom = custom
+	}
+}
+
+// reorderRequest は並べ替え後のエントリIDの一覧です
+type reorderRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+// statusError はステータスコード付きのエラーです
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return e.message
+}
+
+// dayChange は1日分のエントリへの変更です
+// 変更後のエントリと、検証が必要な変更したエントリの位置（検証しない場合は -1、日全体のみ検証する場合は changedDay）を返します
+type dayChange func(entries []models.TimeEntry) (result []models.TimeEntry, changed int, err error)
+
+// changedDay は複数の行を追加した変更（コピーや取り込み）で、日全体の検証のみを行うことを表します
+const changedDay = -2
+
+// dateParam はパスの日付を検証して返します
+// 不正な場合はエラーレスポンスを書き込み、ok=false を返します
+func dateParam(c *gin.Context) (string, bool) {
+	date := c.Param("date")
+	if !isValidDate(date) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "日付の形式が正しくありません（YYYY-MM-DD）"})
+		return "", false
+	}
+	return date, true
+}
+
+// changeDay は1日分のエントリを読み込み、change を適用して保存します
+// 読み込みから保存までは同じ日の他の保存と排他し、If-Match が指定された場合はバージョンを確認します
+func (h *Handler) changeDay(c *gin.Context, date string, status int, change dayChange, respond func(entries []models.TimeEntry, changed int) gin.H) {
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+
+	unlock := h.dayLocks.lock(auth.UserName(c), date)
+	defer unlock()
+
+	current, err := h.repo.GetTimeEntries(ctx, date)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if !checkIfMatch(c, current) {
+		return
+	}
+
+	entries, changed, err := change(models.EnsureEntryIDs(current))
+	if err != nil {
+		if se, ok := err.(*statusError); ok {
+			c.JSON(se.status, gin.H{"error": se.message})
+			return
+		}
+		respondError(c, err)
+		return
+	}
+
+	if changed >= 0 || changed == changedDay {
+		if changed >= 0 {
+			models.NormalizeCustom(&entries[changed], h.validator.CustomFields)
+		}
+
+		// 他の行に既存の誤りがあっても、変更した行と日全体の検証のみを行う
+		// 新しい日は追加したテンプレートの行も含めてすべての行を検証する
+		items, err := h.repo.GetDbItems(ctx)
+		if err != nil {
+			fmt.Printf("業務データベースの取得に失敗したため照合をスキップします: %v\n", err)
+			items = nil
+		}
+		var errs validation.Errors
+		for _, fe := range h.validator.Validate(entries, items) {
+			if fe.Index == changed || fe.Index == validation.DayIndex || len(current) == 0 {
+				errs = append(errs, fe)
+			}
+		}
+		if len(errs) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":  "入力内容に誤りがあります",
+				"errors": errs,
+			})
+			return
+		}
+	}
+
+	updatedAt, err := h.repo.SaveTimeEntries(ctx, date, entries)
+	respondSaved(c, status, entries, updatedAt, err, respond(entries, changed))
+}
+
+// CreateTimeEntry はエントリを1件追加します
+// パスにIDが指定された場合はそのIDで、省略された場合は新しいIDで追加します
+func (h *Handler) CreateTimeEntry(c *gin.Context) {
+	date, ok := dateParam(c)
+	if !ok {
+		return
+	}
+
+	var req createEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry := req.TimeEntry
+	if id := c.Param("id"); id != "" {
+		entry.ID = id
+	}
+	if entry.ID == "" {
+		entry.ID = models.NewEntryID()
+	}
+	if req.Position != nil && *req.Position < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "position は0以上を指定してください"})
+		return
+	}
+
+	h.changeDay(c, date, http.StatusCreated, func(entries []models.TimeEntry) ([]models.TimeEntry, int, error) {
+		if models.FindEntry(entries, entry.ID) >= 0 {
+			return nil, 0, &statusError{http.StatusConflict, fmt.Sprintf("ID %s のエントリは既に存在します", entry.ID)}
+		}
+		if len(entries) == 0 {
+			entries = h.newDayTemplates(c, date, []models.TimeEntry{entry})
+		}
+		pos := len(entries)
+		if req.Position != nil && *req.Position < pos {
+			pos = *req.Position
+		}
+		result := make([]models.TimeEntry, 0, len(entries)+1)
+		result = append(result, entries[:pos]...)
+		result = append(result, entry)
+		result = append(result, entries[pos:]...)
+		return result, pos, nil
+	}, func(entries []models.TimeEntry, changed int) gin.H {
+		return gin.H{"entry": toFrontendEntries(entries)[changed]}
+	})
+}
+
+// UpdateTimeEntry はエントリの指定された項目のみを更新します
+func (h *Handler) UpdateTimeEntry(c *gin.Context) {
+	date, ok := dateParam(c)
+	if !ok {
+		return
+	}
+
+	var patch entryPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id := c.Param("id")
+
+	h.changeDay(c, date, http.StatusOK, func(entries []models.TimeEntry) ([]models.TimeEntry, int, error) {
+		i := models.FindEntry(entries, id)
+		if i < 0 {
+			return nil, 0, &statusError{http.StatusNotFound, fmt.Sprintf("ID %s のエントリが見つかりません", id)}
+		}
+		patch.apply(&entries[i])
+		return entries, i, nil
+	}, func(entries []models.TimeEntry, changed int) gin.H {
+		return gin.H{"entry": toFrontendEntries(entries)[changed]}
+	})
+}
+
+// DeleteTimeEntry はエントリを1件削除します
+func (h *Handler) DeleteTimeEntry(c *gin.Context) {
+	date, ok := dateParam(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+
+	h.changeDay(c, date, http.StatusOK, func(entries []models.TimeEntry) ([]models.TimeEntry, int, error) {
+		i := models.FindEntry(entries, id)
+		if i < 0 {
+			return nil, 0, &statusError{http.StatusNotFound, fmt.Sprintf("ID %s のエントリが見つかりません", id)}
+		}
+		return append(entries[:i], entries[i+1:]...), -1, nil
+	}, func(entries []models.TimeEntry, changed int) gin.H {
+		return gin.H{"message": "削除しました", "id": id}
+	})
+}
+
+// ReorderTimeEntries はエントリを指定されたIDの順に並べ替えます
+// ids にはその日のすべてのエントリのIDを1回ずつ指定する必要があります
+func (h *Handler) ReorderTimeEntries(c *gin.Context) {
+	date, ok := dateParam(c)
+	if !ok {
+		return
+	}
+
+	var req reorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.changeDay(c, date, http.StatusOK, func(entries []models.TimeEntry) ([]models.TimeEntry, int, error) {
+		// 取得後に追加・削除されたエントリがある場合は並べ替えない
+		if len(req.IDs) != len(entries) {
+			return nil, 0, &statusError{http.StatusConflict, "ids がこの日のエントリと一致しません。最新の内容を取得してください"}
+		}
+		result := make([]models.TimeEntry, 0, len(entries))
+		used := make(map[string]bool, len(req.IDs))
+		for _, id := range req.IDs {
+			i := models.FindEntry(entries, id)
+			if i < 0 || used[id] {
+				return nil, 0, &statusError{http.StatusConflict, "ids がこの日のエントリと一致しません。最新の内容を取得してください"}
+			}
+			used[id] = true
+			result = append(result, entries[i])
+		}
+		return result, -1, nil
+	}, func(entries []models.TimeEntry, changed int) gin.H {
+		return gin.H{"entries": toFrontendEntries(entries)}
+	})
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/auth"
+	"github.com/yourusername/timeslice-app/internal/repository"
+	"github.com/yourusername/timeslice-app/internal/timesheet"
+)
+
+// respondError はリポジトリ・認証・タイムシートのエラーを内容に応じたHTTPステータスで返します
+func respondError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrRateLimited):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, repository.ErrUnavailable):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, repository.ErrPermissionDenied):
+		// クライアントではなくサーバーの認証情報の問題のため 502 とする
+		status = http.StatusBadGateway
+	case errors.Is(err, repository.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+	case errors.Is(err, auth.ErrUserNotFound), errors.Is(err, auth.ErrTeamNotFound), errors.Is(err, auth.ErrNotTeamMember):
+		status = http.StatusNotFound
+	case errors.Is(err, auth.ErrUserExists), errors.Is(err, auth.ErrTeamExists):
+		status = http.StatusConflict
+	case errors.Is(err, timesheet.ErrLocked), errors.Is(err, timesheet.ErrInvalidTransition):
+		status = http.StatusConflict
+	case errors.Is(err, timesheet.ErrCommentRequired):
+		status = http.StatusBadRequest
+	case errors.Is(err, timesheet.ErrApproverRequired):
+		status = http.StatusForbidden
+	}
+
+	var apiErr *repository.APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		c.Header("Retry-After", fmt.Sprintf("%d", int(math.Ceil(apiErr.RetryAfter.Seconds()))))
+	}
+
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"hash/fnv"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// dayETag は1日分のエントリのバージョン（ETag）を返します
+func dayETag(entries []models.TimeEntry) string {
+	return `"` + models.EntriesHash(entries)[:20] + `"`
+}
+
+// etagMatches は If-Match / If-None-Match ヘッダーの値に etag が含まれるかどうかを判定します
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		// 弱いETagとして送られた場合も同じ値とみなす
+		candidate = strings.TrimPrefix(candidate, "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// dayLockStripes は日付ごとの排他に使用するロックの数です
+const dayLockStripes = 64
+
+// dayLocks は同じ日の「バージョン確認 → 保存」が同時に実行されないようユーザーの日付ごとに排他します
+// ユーザーと日付のハッシュで固定数のロックから選ぶため、日付が増えてもロックは増えません
+// （別の日が同じロックを共有した場合は待つだけで、結果は変わりません）
+type dayLocks struct {
+	locks [dayLockStripes]sync.Mutex
+}
+
+func (d *dayLocks) lock(user, date string) func() {
+	h := fnv.New32a()
+	h.Write([]byte(user + "/" + date))
+	mu := &d.locks[h.Sum32()%dayLockStripes]
+	mu.Lock()
+	return mu.Unlock
+}
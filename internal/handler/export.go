@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/export"
+)
+
+// ExportTimeEntries は期間内のエントリをCSVまたはExcel形式（format=csv|xlsx）でダウンロードさせます
+// columns で出力する列（カンマ区切り）を、bom=false でCSVの先頭のBOMの省略を指定できます
+func (h *Handler) ExportTimeEntries(c *gin.Context) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", export.FormatCSV)
+	bom := true
+	if s := c.Query("bom"); s != "" {
+		var err error
+		if bom, err = strconv.ParseBool(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bom は true または false を指定してください"})
+			return
+		}
+	}
+	columns, err := export.ParseColumns(c.Query("columns"), h.validator.CustomFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// ダウンロードのヘッダーは最初のデータを取得できて書き込みを始めるときに設定する
+	download := &downloadWriter{
+		c:           c,
+		contentType: export.ContentType(format),
+		disposition: fmt.Sprintf(`attachment; filename="timeslice_%s_%s.%s"`, from, to, format),
+	}
+	w, err := export.NewWriter(format, download, bom)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 期間が長い場合に備えて、同期と同じ処理時間の上限を使用する
+	ctx, cancel := requestContext(c, h.timeouts.Sync)
+	defer cancel()
+
+	_, err = export.Export(ctx, h.repo, from, to, columns, w)
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		if !c.Writer.Written() {
+			respondError(c, err)
+			return
+		}
+		// 書き込みを始めた後はステータスを変更できないため、接続を切断して不完全なファイルとして失敗させる
+		fmt.Printf("エクスポートの途中で失敗しました (%s〜%s): %v\n", from, to, err)
+		if conn, _, err := c.Writer.Hijack(); err == nil {
+			conn.Close()
+		}
+		c.Abort()
+	}
+}
+
+// downloadWriter は最初の書き込みの直前にダウンロードのヘッダー（Content-Type、Content-Disposition）を設定します
+// データの取得に失敗した場合は何も書き込まれないため、通常のエラーのJSONとして応答できます
+type downloadWriter struct {
+	c           *gin.Context
+	contentType string
+	disposition string
+	started     bool
+}
+
+func (d *downloadWriter) Write(p []byte) (int, error) {
+	if !d.started {
+		d.started = true
+		d.c.Header("Content-Type", d.contentType)
+		d.c.Header("Content-Disposition", d.disposition)
+	}
+	return d.c.Writer.Write(p)
+}
@@ -1,35 +1,61 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/audit"
+	"github.com/yourusername/timeslice-app/internal/auth"
 	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/outbox"
 	"github.com/yourusername/timeslice-app/internal/repository"
+	"github.com/yourusername/timeslice-app/internal/syncer"
+	"github.com/yourusername/timeslice-app/internal/timesheet"
+	"github.com/yourusername/timeslice-app/internal/validation"
 )
 
+var datePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
 type Handler struct {
-	repo repository.Repository
+	repo       repository.Repository
+	validator  *validation.Validator
+	syncEngine *syncer.Engine      // nil の場合は同期が無効
+	outbox     *outbox.Outbox      // nil の場合は送信待ちキューが無効
+	auditLog   *audit.Log          // nil の場合は変更履歴が無効
+	timesheets *timesheet.Store    // nil の場合はタイムシートの提出と承認が無効
+	auth       *auth.Authenticator // nil の場合は認証が無効（全員が共通のデータを使用）
+	timeouts   Timeouts
+	dayLocks   dayLocks
+	presetsMu  sync.Mutex // プリセットの読み込みから保存までを排他する
 }
 
 // Define a struct for the frontend time entry format
 type FrontendTimeEntry struct {
-	ID       int    `json:"id"`
-	Time     string `json:"time"`
-	Content  string `json:"content"`
-	Client   string `json:"client"`
-	Purpose  string `json:"purpose"`
-	Action   string `json:"action"`
-	With     string `json:"with"`
-	PcCc     string `json:"pccc"`
-	Remark   string `json:"remark"`
-	Selected bool   `json:"selected"`
+	ID              string `json:"id"`
+	Time            string `json:"time"`
+	DurationMinutes int    `json:"duration_minutes"` // Time を解釈した所要時間（解釈できない場合は0）
+	Start           string `json:"start,omitempty"`  // 時間帯で指定された場合の開始時刻
+	End             string `json:"end,omitempty"`    // 時間帯で指定された場合の終了時刻
+	Content         string `json:"content"`
+	Client          string `json:"client"`
+	Purpose         string `json:"purpose"`
+	Action          string `json:"action"`
+	With            string `json:"with"`
+	PcCc            string `json:"pccc"`
+	Remark          string `json:"remark"`
+	Selected        bool   `json:"selected"`
+
+	Custom map[string]interface{} `json:"custom,omitempty"` // 設定で追加された項目の値
 }
 
 func NewHandler(repo repository.Repository) *Handler {
-	return &Handler{repo: repo}
+	return &Handler{repo: repo, validator: validation.NewValidator(), timeouts: DefaultTimeouts()}
 }
 
 func (h *Handler) GetTimeEntries(c *gin.Context) {
@@ -40,43 +66,120 @@ func (h *Handler) GetTimeEntries(c *gin.Context) {
 	}
 
 	// 日付の形式を確認（YYYY-MM-DD）
-	datePattern := `^\d{4}-\d{2}-\d{2}$`
-	matched, err := regexp.MatchString(datePattern, date)
-	if err != nil || !matched {
+	if !isValidDate(date) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "日付の形式が正しくありません（YYYY-MM-DD）"})
 		return
 	}
 
 	// リポジトリから日付シートのデータを取得
-	backendEntries, err := h.repo.GetTimeEntries(date)
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	backendEntries, err := h.repo.GetTimeEntries(ctx, date)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
+		return
+	}
+
+	// 内容が変わっていなければ本文を返さない
+	etag := dayETag(backendEntries)
+	c.Header("ETag", etag)
+	if match := c.GetHeader("If-None-Match"); match != "" && etagMatches(match, etag) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
 	// Transform data for frontend
-	frontendEntries := make([]FrontendTimeEntry, len(backendEntries))
 	fmt.Printf("バックエンドから %d 件のエントリを取得しました\n", len(backendEntries))
+	frontendEntries := toFrontendEntries(backendEntries)
+	for _, entry := range frontendEntries {
+		fmt.Printf("- エントリ[%s]: 時間=%s, 内容=%s, 備考=%s\n",
+			entry.ID, entry.Time, entry.Content, entry.Remark)
+	}
+
+	// テスト用のダミーデータは不要なので削除
+	c.JSON(http.StatusOK, frontendEntries) // Return the transformed slice
+}
+
+// GetTimeEntriesRange は from〜to の期間のエントリを日付ごとにまとめて返します
+func (h *Handler) GetTimeEntriesRange(c *gin.Context) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	entriesByDate, err := h.repo.GetTimeEntriesRange(ctx, from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	result := make(map[string][]FrontendTimeEntry, len(entriesByDate))
+	for date, entries := range entriesByDate {
+		result[date] = toFrontendEntries(entries)
+	}
 
-	for i, entry := range backendEntries {
+	c.JSON(http.StatusOK, result)
+}
+
+// toFrontendEntries はリポジトリのエントリをフロントエンド形式に変換します
+func toFrontendEntries(entries []models.TimeEntry) []FrontendTimeEntry {
+	frontendEntries := make([]FrontendTimeEntry, len(entries))
+	for i, entry := range entries {
+		// 解釈できない時間はそのまま返し、所要時間は0とする
+		slot, _ := entry.Slot()
+		id := entry.ID
+		if id == "" {
+			id = strconv.Itoa(i + 1) // IDのないエントリは行番号で代用する
+		}
 		frontendEntries[i] = FrontendTimeEntry{
-			ID:       i + 1, // Simple ID based on row number (starts from 1)
-			Time:     entry.Time,
-			Content:  entry.Content,
-			Client:   entry.Client,
-			Purpose:  entry.Purpose,
-			Action:   entry.Action,
-			With:     entry.With,
-			PcCc:     entry.PcCc,
-			Remark:   entry.Remark,
-			Selected: false, // Default to not selected
+			ID:              id,
+			Time:            entry.Time,
+			DurationMinutes: slot.Minutes,
+			Start:           slot.Start,
+			End:             slot.End,
+			Content:         entry.Content,
+			Client:          entry.Client,
+			Purpose:         entry.Purpose,
+			Action:          entry.Action,
+			With:            entry.With,
+			PcCc:            entry.PcCc,
+			Remark:          entry.Remark,
+			Selected:        false, // Default to not selected
+			Custom:          entry.Custom,
 		}
-		fmt.Printf("- エントリ[%d]: 時間=%s, 内容=%s, 備考=%s\n",
-			i+1, entry.Time, entry.Content, entry.Remark)
 	}
+	return frontendEntries
+}
 
-	// テスト用のダミーデータは不要なので削除
-	c.JSON(http.StatusOK, frontendEntries) // Return the transformed slice
+// isValidDate は日付が YYYY-MM-DD 形式の実在する日付かどうかを判定します
+func isValidDate(date string) bool {
+	if !datePattern.MatchString(date) {
+		return false
+	}
+	_, err := time.Parse("2006-01-02", date)
+	return err == nil
+}
+
+// parseDateRange はクエリパラメータ from/to を検証して返します
+// 不正な場合はエラーレスポンスを書き込み、ok=false を返します
+func parseDateRange(c *gin.Context) (from, to string, ok bool) {
+	from = c.Query("from")
+	to = c.Query("to")
+	if from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from と to を指定してください（YYYY-MM-DD）"})
+		return "", "", false
+	}
+	if !isValidDate(from) || !isValidDate(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "日付の形式が正しくありません（YYYY-MM-DD）"})
+		return "", "", false
+	}
+	if from > to {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from は to 以前の日付を指定してください"})
+		return "", "", false
+	}
+	return from, to, true
 }
 
 func (h *Handler) SaveTimeEntries(c *gin.Context) {
@@ -86,22 +189,112 @@ func (h *Handler) SaveTimeEntries(c *gin.Context) {
 		return
 	}
 
+	if !isValidDate(date) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "日付の形式が正しくありません（YYYY-MM-DD）"})
+		return
+	}
+
 	var entries []models.TimeEntry
 	if err := c.ShouldBindJSON(&entries); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	updatedAt, err := h.repo.SaveTimeEntries(date, entries)
+	for i := range entries {
+		models.NormalizeCustom(&entries[i], h.validator.CustomFields)
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+
+	// If-Match が指定された場合は、取得後に他のユーザーが変更していないことを確認してから保存する
+	// 新しい日にエントリを保存する場合は、曜日のテンプレートを先頭に追加する
+	unlock := h.dayLocks.lock(auth.UserName(c), date)
+	defer unlock()
+	var templates []models.TimeEntry
+	if c.GetHeader("If-Match") != "" || len(entries) > 0 {
+		current, err := h.repo.GetTimeEntries(ctx, date)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if !checkIfMatch(c, current) {
+			return
+		}
+		if len(current) == 0 && len(entries) > 0 {
+			templates = h.newDayTemplates(c, date, entries)
+		}
+	}
+	entries = append(templates, entries...)
+
+	// テンプレートを含めて検証する（業務データベースが取得できない場合は照合をスキップする）
+	items, err := h.repo.GetDbItems(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		fmt.Printf("業務データベースの取得に失敗したため照合をスキップします: %v\n", err)
+		items = nil
+	}
+	if errs := templateErrors(h.validator.Validate(entries, items), templates); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  "入力内容に誤りがあります",
+			"errors": errs,
+		})
+		return
+	}
+
+	entries = models.EnsureEntryIDs(entries)
+	updatedAt, err := h.repo.SaveTimeEntries(ctx, date, entries)
+	respondSaved(c, http.StatusOK, entries, updatedAt, err, gin.H{"entries": toFrontendEntries(entries)})
+}
+
+// checkIfMatch は If-Match ヘッダーと現在の内容のバージョンを比較します
+// 一致しない場合は 412 と最新の内容を書き込み、false を返します
+func checkIfMatch(c *gin.Context, current []models.TimeEntry) bool {
+	match := c.GetHeader("If-Match")
+	if match == "" {
+		return true
+	}
+	etag := dayETag(current)
+	if etagMatches(match, etag) {
+		return true
+	}
+	c.Header("ETag", etag)
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":   "この日のデータは他の場所で更新されています。最新の内容を確認してから保存してください",
+		"etag":    etag,
+		"current": toFrontendEntries(current),
+	})
+	return false
+}
+
+// respondSaved は1日分のエントリを保存した結果を status で返します
+// extra はレスポンスに追加する項目です（保存したエントリなど）
+func respondSaved(c *gin.Context, status int, entries []models.TimeEntry, updatedAt time.Time, err error, extra gin.H) {
+	var queued *repository.QueuedError
+	if err == nil || errors.As(err, &queued) {
+		c.Header("ETag", dayETag(entries))
+	}
+	if err != nil && queued == nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	body := gin.H{
 		"message":    "保存しました",
+		"status":     "saved",
 		"updated_at": updatedAt.Format("2006/01/02 15:04:05"),
-	})
+	}
+	for key, value := range extra {
+		body[key] = value
+	}
+	if queued != nil {
+		// スプレッドシートには未反映だが、保存は受け付けている
+		body["message"] = "送信待ちキューに登録しました。接続が回復し次第スプレッドシートに反映します"
+		body["status"] = "queued"
+		body["queue_id"] = queued.ID
+		c.JSON(http.StatusAccepted, body)
+		return
+	}
+	c.JSON(status, body)
 }
 
 func (h *Handler) GetDbItems(c *gin.Context) {
@@ -109,18 +302,21 @@ func (h *Handler) GetDbItems(c *gin.Context) {
 	var rawItems []models.DbItem
 	var err error
 
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+
 	if source == "spreadsheet" {
 		// スプレッドシートから直接データを取得
-		rawItems, err = h.repo.GetDbItems()
+		rawItems, err = h.repo.GetDbItems(ctx)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 	} else {
 		// 通常のデータベースから取得
-		rawItems, err = h.repo.GetDbItems()
+		rawItems, err = h.repo.GetDbItems(ctx)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondError(c, err)
 			return
 		}
 	}
@@ -136,65 +332,22 @@ func (h *Handler) GetDbItems(c *gin.Context) {
 		Remark:  []string{},
 	}
 
-	// Map old spreadsheet types to new types
-	typeMap := map[string]string{
-		"内容":     "content",
-		"クライアント": "client",
-		"クライアント（誰に、誰のために）": "client",
-		"目的":      "purpose",
-		"アクション":   "action",
-		"誰と":      "with",
-		"PC/CC":   "pccc",
-		"備考":      "remark",
-		"content": "content",
-		"client":  "client",
-		"purpose": "purpose",
-		"action":  "action",
-		"with":    "with",
-		"pccc":    "pccc",
-		"remark":  "remark",
-		// 古いタイプも新しい形式に変換する
-		"task":     "content",
-		"function": "action",
-		"mall":     "with",
-		"costtype": "pccc",
-	}
-
 	// 重複を避けるためのセット
-	uniqueValues := map[string]map[string]bool{
-		"content": {},
-		"client":  {},
-		"purpose": {},
-		"action":  {},
-		"with":    {},
-		"pccc":    {},
-		"remark":  {},
-	}
+	uniqueValues := map[string]map[string]bool{}
 
 	for _, item := range rawItems {
-		if newType, ok := typeMap[item.Type]; ok {
-			// 重複を避ける
-			if _, exists := uniqueValues[newType][item.Value]; !exists {
-				uniqueValues[newType][item.Value] = true
-
-				// 適切なフィールドに追加
-				switch newType {
-				case "content":
-					dbItems.Content = append(dbItems.Content, item.Value)
-				case "client":
-					dbItems.Client = append(dbItems.Client, item.Value)
-				case "purpose":
-					dbItems.Purpose = append(dbItems.Purpose, item.Value)
-				case "action":
-					dbItems.Action = append(dbItems.Action, item.Value)
-				case "with":
-					dbItems.With = append(dbItems.With, item.Value)
-				case "pccc":
-					dbItems.PcCc = append(dbItems.PcCc, item.Value)
-				case "remark":
-					dbItems.Remark = append(dbItems.Remark, item.Value)
-				}
-			}
+		// 古いタイプや日本語の見出しも新しい形式に変換する
+		field, ok := models.LookupField(item.Type)
+		if !ok || !field.DbItem {
+			continue
+		}
+		if uniqueValues[field.Key] == nil {
+			uniqueValues[field.Key] = map[string]bool{}
+		}
+		// 重複を避ける
+		if !uniqueValues[field.Key][item.Value] {
+			uniqueValues[field.Key][item.Value] = true
+			dbItems.Add(field.Key, item.Value)
 		}
 	}
 
@@ -204,14 +357,21 @@ func (h *Handler) GetDbItems(c *gin.Context) {
 }
 
 func (h *Handler) SaveDbItems(c *gin.Context) {
+	// 業務データベースは全員で共通のため、変更は管理者のみ
+	if !h.requireRole(c, auth.RoleAdmin) {
+		return
+	}
+
 	var items []models.DbItem
 	if err := c.ShouldBindJSON(&items); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.repo.SaveDbItems(items); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	if err := h.repo.SaveDbItems(ctx, items); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -219,14 +379,21 @@ func (h *Handler) SaveDbItems(c *gin.Context) {
 }
 
 func (h *Handler) DeleteDbItems(c *gin.Context) {
+	// 業務データベースは全員で共通のため、変更は管理者のみ
+	if !h.requireRole(c, auth.RoleAdmin) {
+		return
+	}
+
 	var items []models.DbItem
 	if err := c.ShouldBindJSON(&items); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.repo.DeleteDbItems(items); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	if err := h.repo.DeleteDbItems(ctx, items); err != nil {
+		respondError(c, err)
 		return
 	}
 
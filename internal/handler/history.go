@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/audit"
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// SetAuditLog は変更履歴の参照と復元を有効にします
+func (h *Handler) SetAuditLog(l *audit.Log) {
+	h.auditLog = l
+}
+
+// auditEnabled は変更履歴が無効な場合にエラーレスポンスを書き込み、false を返します
+func (h *Handler) auditEnabled(c *gin.Context) bool {
+	if h.auditLog == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "変更履歴が設定されていません（audit.enabled: true が必要です）"})
+		return false
+	}
+	return true
+}
+
+// historyLimit はクエリパラメータ limit を返します（省略時は既定値）
+func historyLimit(c *gin.Context) (int, bool) {
+	value := c.Query("limit")
+	if value == "" {
+		return audit.DefaultLimit, true
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit には1以上の整数を指定してください"})
+		return 0, false
+	}
+	return limit, true
+}
+
+// GetTimeEntriesHistory は日付の変更履歴を新しい順に返します
+// クエリ: limit（省略時は50件）
+func (h *Handler) GetTimeEntriesHistory(c *gin.Context) {
+	if !h.auditEnabled(c) {
+		return
+	}
+	date, ok := dateParam(c)
+	if !ok {
+		return
+	}
+	limit, ok := historyLimit(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	records, err := h.auditLog.List(ctx, audit.KindTimeEntries, date, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+// GetDbItemsHistory は業務データベースの変更履歴を新しい順に返します
+// クエリ: limit（省略時は50件）
+func (h *Handler) GetDbItemsHistory(c *gin.Context) {
+	if !h.auditEnabled(c) {
+		return
+	}
+	limit, ok := historyLimit(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	records, err := h.auditLog.List(ctx, audit.KindDbItems, "", limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+// RestoreTimeEntries は日付の内容を変更履歴の版に戻します
+// 既定では記録の変更後（after）の内容に、state=before の場合は変更前の内容に戻します
+// 復元自体も新しい記録として残るため、復元を取り消すこともできます
+func (h *Handler) RestoreTimeEntries(c *gin.Context) {
+	if !h.auditEnabled(c) {
+		return
+	}
+	date, ok := dateParam(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "履歴のIDが正しくありません"})
+		return
+	}
+	state := c.DefaultQuery("state", "after")
+	if state != "after" && state != "before" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "state には after または before を指定してください"})
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	record, err := h.auditLog.Get(ctx, id)
+	cancel()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	// 他のユーザーの記録は存在しないものとして扱う
+	if record == nil || record.Kind != audit.KindTimeEntries || record.Date != date || record.User != repository.UserFromContext(ctx) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "指定された履歴が見つかりません"})
+		return
+	}
+
+	snapshot := record.After
+	if state == "before" {
+		snapshot = record.Before
+	}
+	var version []models.TimeEntry
+	if err := json.Unmarshal(snapshot, &version); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Request = c.Request.WithContext(audit.WithAction(c.Request.Context(), audit.ActionRestore))
+	h.changeDay(c, date, http.StatusOK, func([]models.TimeEntry) ([]models.TimeEntry, int, error) {
+		return version, -1, nil
+	}, func(entries []models.TimeEntry, changed int) gin.H {
+		return gin.H{"message": "復元しました", "restored_from": record.ID, "entries": toFrontendEntries(entries)}
+	})
+}
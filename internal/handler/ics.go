@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/ics"
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// maxICSSize はアップロードできるカレンダーファイルのサイズの上限です
+const maxICSSize = 10 << 20
+
+// locationParam はクエリの tz で指定されたタイムゾーンを返します（指定がない場合はサーバーのタイムゾーン）
+func locationParam(c *gin.Context) (*time.Location, bool) {
+	tz := c.Query("tz")
+	if tz == "" {
+		return time.Local, true
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("タイムゾーンが正しくありません: %s", tz)})
+		return nil, false
+	}
+	return loc, true
+}
+
+// ImportICS はアップロードされたカレンダー（.ics）から :date の予定を取り込み、エントリとして追加します
+// ファイルはフォームの file で送信するか、本文にそのまま指定します（Content-Type: text/calendar）
+// tz でタイムゾーンの指定がない日時を解釈するタイムゾーン（例: Asia/Tokyo）を指定できます
+func (h *Handler) ImportICS(c *gin.Context) {
+	date, ok := dateParam(c)
+	if !ok {
+		return
+	}
+
+	loc, ok := locationParam(c)
+	if !ok {
+		return
+	}
+	day, _ := time.ParseInLocation("2006-01-02", date, loc)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxICSSize)
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "カレンダーファイル（file）が指定されていません"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	events, err := ics.Parse(body, loc)
+	if err == nil {
+		events, err = ics.EventsOn(events, day)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(events) == 0 {
+		// 予定がない日は保存しない（スプレッドシートに空の日付シートを作成しない）
+		c.JSON(http.StatusOK, gin.H{"message": "この日の予定はありません", "imported": 0, "skipped": 0})
+		return
+	}
+
+	// 業務データベースが取得できない場合は対応付けをせずに取り込む
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	items, err := h.repo.GetDbItems(ctx)
+	cancel()
+	if err != nil {
+		fmt.Printf("業務データベースの取得に失敗したため対応付けをスキップします: %v\n", err)
+		items = nil
+	}
+
+	// 合計時間などの日全体の検証を行い、新しい日には曜日のテンプレートを先頭に追加する
+	var added []models.TimeEntry
+	h.changeDay(c, date, http.StatusOK, func(entries []models.TimeEntry) ([]models.TimeEntry, int, error) {
+		var merged []models.TimeEntry
+		merged, added = ics.MergeDay(entries, events, day, items)
+		if len(entries) == 0 {
+			merged = append(h.newDayTemplates(c, date, added), merged...)
+		}
+		return merged, changedDay, nil
+	}, func(entries []models.TimeEntry, changed int) gin.H {
+		return gin.H{
+			"imported": len(added),
+			"skipped":  len(events) - len(added), // 取り込み済みの予定
+			"entries":  toFrontendEntries(entries),
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/auth"
+	"github.com/yourusername/timeslice-app/internal/outbox"
+)
+
+// SetOutbox は送信待ちキューの状態取得を有効にします
+func (h *Handler) SetOutbox(o *outbox.Outbox) {
+	h.outbox = o
+}
+
+// GetQueueStatus はログインしているユーザーの送信待ちキューの状態を返します
+func (h *Handler) GetQueueStatus(c *gin.Context) {
+	if h.outbox == nil {
+		c.JSON(http.StatusOK, gin.H{"enabled": false})
+		return
+	}
+
+	status, err := h.outbox.Status(auth.UserName(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"enabled": true, "queue": status})
+}
@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/auth"
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// presetChange はプリセットの一覧への変更です。変更後の一覧を返します
+type presetChange func(presets []models.Preset) ([]models.Preset, error)
+
+// changePresets はプリセットを読み込み、change を適用して保存します
+// プリセットはシート全体を書き換えるため、読み込みから保存までは他の変更と排他します
+func (h *Handler) changePresets(c *gin.Context, change presetChange) bool {
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+
+	h.presetsMu.Lock()
+	defer h.presetsMu.Unlock()
+
+	current, err := h.repo.GetPresets(ctx)
+	if err != nil {
+		respondError(c, err)
+		return false
+	}
+
+	presets, err := change(current)
+	if err != nil {
+		if se, ok := err.(*statusError); ok {
+			c.JSON(se.status, gin.H{"error": se.message})
+			return false
+		}
+		respondError(c, err)
+		return false
+	}
+
+	if err := h.repo.SavePresets(ctx, presets); err != nil {
+		respondError(c, err)
+		return false
+	}
+	return true
+}
+
+// presetVisible はプリセットがログインしているユーザーに表示されるかどうかを判定します
+// 認証が無効の場合は利用者を確認できないため、すべてのプリセットをチーム全員のものとして扱います
+func (h *Handler) presetVisible(c *gin.Context, p models.Preset) bool {
+	return h.auth == nil || p.VisibleTo(auth.UserName(c))
+}
+
+// presetEditable はログインしているユーザーがプリセットを変更・削除できるかどうかを判定します
+// 自分のプリセットは作成者、共有プリセットは作成者とマネージャー・管理者のみが変更できます
+func (h *Handler) presetEditable(c *gin.Context, p models.Preset) bool {
+	return h.auth == nil || p.Owner == auth.UserName(c) ||
+		p.Shared && auth.UserRole(c).AtLeast(auth.RoleManager)
+}
+
+// presetOwner は新しいプリセットの作成者と、共有プリセットとして作成するかどうかを返します
+// 認証が無効の場合は作成者を記録せず、すべて共有プリセットとして作成します
+func (h *Handler) presetOwner(c *gin.Context, shared bool) (owner string, isShared bool) {
+	if h.auth == nil {
+		return "", true
+	}
+	return auth.UserName(c), shared
+}
+
+// checkPresets はプリセットのカスタム項目と曜日を正規化して検証します。誤りがある場合は 422 を書き込み、false を返します
+func (h *Handler) checkPresets(c *gin.Context, presets []models.Preset) bool {
+	for i := range presets {
+		models.NormalizeCustom(&presets[i].TimeEntry, h.validator.CustomFields)
+		presets[i].NormalizeWeekdays()
+	}
+	if errs := h.validator.ValidatePresets(presets); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  "入力内容に誤りがあります",
+			"errors": errs,
+		})
+		return false
+	}
+	return true
+}
+
+// GetPresets は共有プリセットと自分のプリセットを返します
+// scope=shared または scope=personal で一方のみに絞り込めます
+func (h *Handler) GetPresets(c *gin.Context) {
+	scope := c.Query("scope")
+	if scope != "" && scope != "shared" && scope != "personal" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scope は shared または personal を指定してください"})
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+
+	presets, err := h.repo.GetPresets(ctx)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	visible := []models.Preset{}
+	for _, p := range presets {
+		if !h.presetVisible(c, p) || (scope == "shared" && !p.Shared) || (scope == "personal" && p.Shared) {
+			continue
+		}
+		visible = append(visible, p)
+	}
+	c.JSON(http.StatusOK, gin.H{"presets": visible})
+}
+
+// CreatePreset はプリセットを追加します（ID を省略した場合は新しいIDを割り当てます）
+func (h *Handler) CreatePreset(c *gin.Context) {
+	var preset models.Preset
+	if err := c.ShouldBindJSON(&preset); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if preset.ID == "" {
+		preset.ID = models.NewEntryID()
+	}
+	preset.Owner, preset.Shared = h.presetOwner(c, preset.Shared)
+	checked := []models.Preset{preset}
+	if !h.checkPresets(c, checked) {
+		return
+	}
+	preset = checked[0]
+
+	ok := h.changePresets(c, func(presets []models.Preset) ([]models.Preset, error) {
+		if models.FindPreset(presets, preset.ID) >= 0 {
+			return nil, &statusError{http.StatusConflict, fmt.Sprintf("ID %s のプリセットは既に存在します", preset.ID)}
+		}
+		return append(presets, preset), nil
+	})
+	if ok {
+		c.JSON(http.StatusCreated, gin.H{"message": "保存しました", "preset": preset})
+	}
+}
+
+// UpdatePreset はプリセットの内容を置き換えます
+// 共有プリセットは作成者とマネージャー・管理者が変更できますが、共有するかどうかは作成者のみが変更できます
+func (h *Handler) UpdatePreset(c *gin.Context) {
+	var preset models.Preset
+	if err := c.ShouldBindJSON(&preset); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	preset.ID = c.Param("id")
+	checked := []models.Preset{preset}
+	if !h.checkPresets(c, checked) {
+		return
+	}
+	preset = checked[0]
+
+	ok := h.changePresets(c, func(presets []models.Preset) ([]models.Preset, error) {
+		i := models.FindPreset(presets, preset.ID)
+		if i < 0 || !h.presetVisible(c, presets[i]) {
+			return nil, &statusError{http.StatusNotFound, fmt.Sprintf("ID %s のプリセットが見つかりません", preset.ID)}
+		}
+		if !h.presetEditable(c, presets[i]) {
+			return nil, &statusError{http.StatusForbidden, "共有プリセットは作成者またはマネージャー・管理者のみが変更できます"}
+		}
+		if h.auth == nil {
+			preset.Shared = true
+		} else if preset.Shared != presets[i].Shared && presets[i].Owner != auth.UserName(c) {
+			return nil, &statusError{http.StatusForbidden, "共有の設定は作成者のみが変更できます"}
+		}
+		preset.Owner = presets[i].Owner
+		presets[i] = preset
+		return presets, nil
+	})
+	if ok {
+		c.JSON(http.StatusOK, gin.H{"message": "保存しました", "preset": preset})
+	}
+}
+
+// DeletePreset はプリセットを削除します
+func (h *Handler) DeletePreset(c *gin.Context) {
+	id := c.Param("id")
+	ok := h.changePresets(c, func(presets []models.Preset) ([]models.Preset, error) {
+		i := models.FindPreset(presets, id)
+		if i < 0 || !h.presetVisible(c, presets[i]) {
+			return nil, &statusError{http.StatusNotFound, fmt.Sprintf("ID %s のプリセットが見つかりません", id)}
+		}
+		if !h.presetEditable(c, presets[i]) {
+			return nil, &statusError{http.StatusForbidden, "共有プリセットは作成者またはマネージャー・管理者のみが削除できます"}
+		}
+		return append(presets[:i], presets[i+1:]...), nil
+	})
+	if ok {
+		c.JSON(http.StatusOK, gin.H{"message": "削除しました"})
+	}
+}
+
+// ImportPresets はブラウザに保存されていたプリセット（localStorage の timeslice-presets の JSON）を取り込みます
+// shared=true の場合（認証が無効の場合は常に）共有プリセットとして取り込みます
+// 自分のプリセットと同じIDのものは上書きし、他の利用者のプリセットや取り込む中でIDが重複する場合は新しいIDを割り当てます
+func (h *Handler) ImportPresets(c *gin.Context) {
+	var imported []models.Preset
+	if err := c.ShouldBindJSON(&imported); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	shared := false
+	if s := c.Query("shared"); s != "" {
+		var err error
+		if shared, err = strconv.ParseBool(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "shared は true または false を指定してください"})
+			return
+		}
+	}
+
+	owner, shared := h.presetOwner(c, shared)
+	for i := range imported {
+		imported[i].Shared = shared
+		imported[i].Owner = owner
+	}
+	if !h.checkPresets(c, imported) {
+		return
+	}
+
+	ok := h.changePresets(c, func(presets []models.Preset) ([]models.Preset, error) {
+		seen := make(map[string]bool)
+		for i, p := range imported {
+			j := -1
+			if p.ID != "" && !seen[p.ID] {
+				j = models.FindPreset(presets, p.ID)
+			}
+			switch {
+			case j >= 0 && presets[j].Owner == owner:
+				presets[j] = p
+			case j >= 0 || p.ID == "" || seen[p.ID]:
+				p.ID = models.NewEntryID()
+				fallthrough
+			default:
+				presets = append(presets, p)
+			}
+			seen[p.ID] = true
+			imported[i] = p
+		}
+		return presets, nil
+	})
+	if ok {
+		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("%d 件のプリセットを取り込みました", len(imported)), "presets": imported})
+	}
+}
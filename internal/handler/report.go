@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/report"
+)
+
+// GetReportSummary は期間内の作業時間を項目別・項目の組み合わせ別に集計して返します
+func (h *Handler) GetReportSummary(c *gin.Context) {
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	groupBy, err := report.ParseGroupBy(c.Query("group_by"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	summary, err := report.Generate(ctx, h.repo, from, to, groupBy)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// schemaField は画面に表示するタイムエントリの項目の定義です
+type schemaField struct {
+	Key     string   `json:"key"`
+	Label   string   `json:"label"`
+	Type    string   `json:"type"`
+	Custom  bool     `json:"custom"`            // 設定で追加された項目かどうか（値は custom に入ります）
+	Options []string `json:"options,omitempty"` // select の選択肢（業務データベースの値）
+}
+
+// SetCustomFields は設定で追加された項目を検証と項目定義の取得に使用します
+func (h *Handler) SetCustomFields(fields []models.CustomField) {
+	h.validator.CustomFields = fields
+}
+
+// GetFieldSchema はタイムエントリの項目の一覧（既定の項目とカスタム項目）を返します
+func (h *Handler) GetFieldSchema(c *gin.Context) {
+	var items []models.DbItem
+	if len(h.validator.CustomFields) > 0 {
+		ctx, cancel := requestContext(c, h.timeouts.Read)
+		defer cancel()
+
+		// 業務データベースが取得できない場合は選択肢なしで返す
+		var err error
+		items, err = h.repo.GetDbItems(ctx)
+		if err != nil {
+			fmt.Printf("業務データベースの取得に失敗したため選択肢を省略します: %v\n", err)
+			items = nil
+		}
+	}
+
+	fields := make([]schemaField, 0, len(models.Fields)+len(h.validator.CustomFields))
+	for _, f := range models.Fields {
+		fields = append(fields, schemaField{Key: f.Key, Label: f.Label, Type: models.CustomText})
+	}
+	for _, f := range h.validator.CustomFields {
+		field := schemaField{Key: f.Key, Label: f.DisplayLabel(), Type: f.Type, Custom: true}
+		if f.Type == models.CustomSelect {
+			for _, item := range items {
+				if f.Matches(item.Type) {
+					field.Options = append(field.Options, item.Value)
+				}
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"fields": fields})
+}
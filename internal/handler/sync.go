@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/audit"
+	"github.com/yourusername/timeslice-app/internal/syncer"
+)
+
+// SetSyncEngine はスプレッドシートとの同期を有効にします
+func (h *Handler) SetSyncEngine(engine *syncer.Engine) {
+	h.syncEngine = engine
+}
+
+// PostSync は期間内のローカルデータとスプレッドシートを同期します
+// クエリ: from, to（必須）, direction（both/push/pull）, resolve（local/remote、省略時は競合を報告のみ）
+func (h *Handler) PostSync(c *gin.Context) {
+	if h.syncEngine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "同期が設定されていません（backend: sqlite と sync.enabled: true が必要です）"})
+		return
+	}
+
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	// 取り込みは変更履歴に同期として記録する
+	c.Request = c.Request.WithContext(audit.WithAction(c.Request.Context(), audit.ActionSync))
+	ctx, cancel := requestContext(c, h.timeouts.Sync)
+	defer cancel()
+	result, err := h.syncEngine.Sync(ctx, syncer.Options{
+		From:      from,
+		To:        to,
+		Direction: c.DefaultQuery("direction", syncer.DirectionBoth),
+		Resolve:   c.Query("resolve"),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+
+	status := http.StatusOK
+	if len(result.Conflicts) > 0 {
+		status = http.StatusConflict
+	}
+	c.JSON(status, result)
+}
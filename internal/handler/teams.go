@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/auth"
+	"github.com/yourusername/timeslice-app/internal/report"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// roleLabels は権限のエラーメッセージに使用する名前です
+var roleLabels = map[auth.Role]string{
+	auth.RoleManager: "マネージャー",
+	auth.RoleAdmin:   "管理者",
+}
+
+// requireRole はログインしているユーザーが role 以上の権限を持たない場合に 403 を書き込み、false を返します
+// 認証が無効の場合は従来どおり制限しません
+func (h *Handler) requireRole(c *gin.Context, role auth.Role) bool {
+	if h.auth == nil || auth.UserRole(c).AtLeast(role) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "この操作には" + roleLabels[role] + "の権限が必要です"})
+	return false
+}
+
+// viewableTeam はログインしているユーザーが閲覧できるチームを返します
+// 管理者はすべてのチーム、マネージャーは所属するチームを閲覧できます
+func (h *Handler) viewableTeam(c *gin.Context) (*auth.Team, bool) {
+	if !h.authEnabled(c) || !h.requireRole(c, auth.RoleManager) {
+		return nil, false
+	}
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	team, err := h.auth.Store.GetTeam(ctx, c.Param("team"))
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	if auth.UserRole(c) != auth.RoleAdmin && !slices.Contains(team.Members, auth.UserName(c)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "所属していないチームは閲覧できません"})
+		return nil, false
+	}
+	return team, true
+}
+
+// teamMember はチームの閲覧を確認し、URLで指定されたメンバーを返します
+func (h *Handler) teamMember(c *gin.Context) (string, bool) {
+	team, ok := h.viewableTeam(c)
+	if !ok {
+		return "", false
+	}
+	member := c.Param("user")
+	if !slices.Contains(team.Members, member) {
+		respondError(c, auth.ErrNotTeamMember)
+		return "", false
+	}
+	return member, true
+}
+
+// GetTeams はチームの一覧を返します（管理者はすべて、それ以外は所属するチーム）
+func (h *Handler) GetTeams(c *gin.Context) {
+	if !h.authEnabled(c) {
+		return
+	}
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+
+	var teams []auth.Team
+	var err error
+	if auth.UserRole(c) == auth.RoleAdmin {
+		teams, err = h.auth.Store.ListTeams(ctx)
+	} else {
+		teams, err = h.auth.Store.UserTeams(ctx, auth.UserName(c))
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, teams)
+}
+
+// CreateTeam はチームを作成します（管理者のみ）
+func (h *Handler) CreateTeam(c *gin.Context) {
+	if !h.authEnabled(c) || !h.requireRole(c, auth.RoleAdmin) {
+		return
+	}
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name, err := auth.ValidateTeamName(req.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	team, err := h.auth.Store.CreateTeam(ctx, name)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, team)
+}
+
+// DeleteTeam はチームを削除します（管理者のみ）
+func (h *Handler) DeleteTeam(c *gin.Context) {
+	if !h.authEnabled(c) || !h.requireRole(c, auth.RoleAdmin) {
+		return
+	}
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	if err := h.auth.Store.DeleteTeam(ctx, c.Param("team")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "削除しました"})
+}
+
+// AddTeamMember はユーザーをチームに追加します（管理者のみ）
+func (h *Handler) AddTeamMember(c *gin.Context) {
+	if !h.authEnabled(c) || !h.requireRole(c, auth.RoleAdmin) {
+		return
+	}
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	if err := h.auth.Store.AddTeamMember(ctx, c.Param("team"), c.Param("user")); err != nil {
+		respondError(c, err)
+		return
+	}
+	team, err := h.auth.Store.GetTeam(ctx, c.Param("team"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+// RemoveTeamMember はユーザーをチームから外します（管理者のみ）
+func (h *Handler) RemoveTeamMember(c *gin.Context) {
+	if !h.authEnabled(c) || !h.requireRole(c, auth.RoleAdmin) {
+		return
+	}
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	if err := h.auth.Store.RemoveTeamMember(ctx, c.Param("team"), c.Param("user")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "削除しました"})
+}
+
+// GetTeamMemberEntries はチームのメンバーの期間内のエントリを返します（閲覧のみ）
+func (h *Handler) GetTeamMemberEntries(c *gin.Context) {
+	member, ok := h.teamMember(c)
+	if !ok {
+		return
+	}
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	entriesByDate, err := h.repo.GetTimeEntriesRange(repository.WithUser(ctx, member), from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	result := make(map[string][]FrontendTimeEntry, len(entriesByDate))
+	for date, entries := range entriesByDate {
+		result[date] = toFrontendEntries(entries)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetTeamMemberDay はチームのメンバーの1日分のエントリを返します（閲覧のみ）
+func (h *Handler) GetTeamMemberDay(c *gin.Context) {
+	member, ok := h.teamMember(c)
+	if !ok {
+		return
+	}
+	date, ok := dateParam(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	entries, err := h.repo.GetTimeEntries(repository.WithUser(ctx, member), date)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toFrontendEntries(entries))
+}
+
+// GetTeamSummary はチームの期間内の作業時間をメンバー別と項目別に集計して返します
+func (h *Handler) GetTeamSummary(c *gin.Context) {
+	team, ok := h.viewableTeam(c)
+	if !ok {
+		return
+	}
+	from, to, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	groupBy, err := report.ParseGroupBy(c.Query("group_by"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	summary, err := report.GenerateTeam(ctx, h.repo, team.Name, team.Members, from, to, groupBy)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetUsers はアカウントと権限の一覧を返します（管理者のみ）
+func (h *Handler) GetUsers(c *gin.Context) {
+	if !h.authEnabled(c) || !h.requireRole(c, auth.RoleAdmin) {
+		return
+	}
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	users, err := h.auth.Store.ListUsers(ctx)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// SetUserRole はアカウントの権限を変更します（管理者のみ）
+// 管理者がいなくならないよう、自分の権限は変更できません
+func (h *Handler) SetUserRole(c *gin.Context) {
+	if !h.authEnabled(c) || !h.requireRole(c, auth.RoleAdmin) {
+		return
+	}
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	role, err := auth.ParseRole(req.Role)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user := c.Param("user")
+	if user == auth.UserName(c) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "自分の権限は変更できません"})
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	if err := h.auth.Store.SetRole(ctx, user, role); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"user": user, "role": role})
+}
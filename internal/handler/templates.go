@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/validation"
+)
+
+// dayTemplates は date の曜日に繰り返すプリセット（テンプレート）からエントリを作成します
+// プリセットが取得できない場合はテンプレートなしとして扱います
+func (h *Handler) dayTemplates(c *gin.Context, date string) []models.TimeEntry {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	presets, err := h.repo.GetPresets(ctx)
+	if err != nil {
+		fmt.Printf("プリセットの取得に失敗したためテンプレートの追加をスキップします: %v\n", err)
+		return nil
+	}
+
+	var entries []models.TimeEntry
+	for _, p := range presets {
+		if h.presetVisible(c, p) && p.RepeatsOn(day.Weekday()) {
+			entries = append(entries, p.Entry())
+		}
+	}
+	return entries
+}
+
+// newDayTemplates は新しい日（保存済みのエントリがない日）に entries を保存する際に、先頭に追加するテンプレートのエントリを返します
+// 同じ内容・クライアントのエントリが entries に既にある場合（前日のコピーなど）はそのテンプレートを追加しません
+func (h *Handler) newDayTemplates(c *gin.Context, date string, entries []models.TimeEntry) []models.TimeEntry {
+	var result []models.TimeEntry
+	for _, t := range h.dayTemplates(c, date) {
+		duplicate := false
+		for _, e := range entries {
+			if e.Content == t.Content && e.Client == t.Client {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// templateErrors は先頭に templates を追加したエントリの検証エラーを、送信された配列の位置に合わせて返します
+// テンプレートの行のエラーは日全体のエラーとして、どのテンプレートかがわかるように返します
+func templateErrors(errs validation.Errors, templates []models.TimeEntry) validation.Errors {
+	n := len(templates)
+	for i, fe := range errs {
+		switch {
+		case fe.Index == validation.DayIndex:
+		case fe.Index < n:
+			errs[i].Index = validation.DayIndex
+			errs[i].Message = fmt.Sprintf("曜日のテンプレート（%s）: %s", templates[fe.Index].Content, fe.Message)
+		default:
+			errs[i].Index -= n
+		}
+	}
+	return errs
+}
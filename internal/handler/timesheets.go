@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/timeslice-app/internal/auth"
+	"github.com/yourusername/timeslice-app/internal/report"
+	"github.com/yourusername/timeslice-app/internal/repository"
+	"github.com/yourusername/timeslice-app/internal/timesheet"
+)
+
+// SetTimesheets はタイムシートの提出と承認を有効にします
+func (h *Handler) SetTimesheets(s *timesheet.Store) {
+	h.timesheets = s
+}
+
+// timesheetsEnabled はタイムシートが無効な場合にエラーレスポンスを書き込み、false を返します
+func (h *Handler) timesheetsEnabled(c *gin.Context) bool {
+	if h.timesheets == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "タイムシートが設定されていません（timesheets.enabled: true が必要です）"})
+		return false
+	}
+	return true
+}
+
+// timesheetResponse はタイムシートと期間内のエントリの合計です
+type timesheetResponse struct {
+	*timesheet.Timesheet
+	Totals report.MemberTotal `json:"totals"`
+}
+
+// periodParam はURLの期間（2026-W42 または 2026-10）を検証して返します
+func periodParam(c *gin.Context) (timesheet.Period, bool) {
+	period, err := timesheet.ParsePeriod(c.Param("period"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return timesheet.Period{}, false
+	}
+	return period, true
+}
+
+// stateQuery はクエリの state（省略可）を検証して返します
+func stateQuery(c *gin.Context) (timesheet.State, bool) {
+	if c.Query("state") == "" {
+		return "", true
+	}
+	state, err := timesheet.ParseState(c.Query("state"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return state, true
+}
+
+// commentBody は本文のJSONの comment（省略可）を返します
+func commentBody(c *gin.Context) (string, bool) {
+	var req struct {
+		Comment string `json:"comment"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return "", false
+		}
+	}
+	return req.Comment, true
+}
+
+// respondTimesheet は user の期間のタイムシートを、期間内のエントリの合計とともに返します
+func (h *Handler) respondTimesheet(ctx context.Context, c *gin.Context, status int, sheet *timesheet.Timesheet) {
+	entriesByDate, err := h.repo.GetTimeEntriesRange(repository.WithUser(ctx, sheet.User), sheet.From, sheet.To)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(status, timesheetResponse{Timesheet: sheet, Totals: report.Total(sheet.User, entriesByDate)})
+}
+
+// GetTimesheets はログインしているユーザーのタイムシートの一覧を返します（state で絞り込み可）
+func (h *Handler) GetTimesheets(c *gin.Context) {
+	if !h.timesheetsEnabled(c) {
+		return
+	}
+	state, ok := stateQuery(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	sheets, err := h.timesheets.List(ctx, []string{auth.UserName(c)}, state)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sheets)
+}
+
+// GetTimesheet はログインしているユーザーの期間のタイムシートを遷移の記録とともに返します
+func (h *Handler) GetTimesheet(c *gin.Context) {
+	if !h.timesheetsEnabled(c) {
+		return
+	}
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	sheet, err := h.timesheets.Get(ctx, auth.UserName(c), period)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.respondTimesheet(ctx, c, http.StatusOK, sheet)
+}
+
+// SubmitTimesheet はログインしているユーザーの期間のタイムシートを提出します
+// 提出後は承認者が差し戻すか、本人が取り下げるまで期間内のエントリを変更できません
+func (h *Handler) SubmitTimesheet(c *gin.Context) {
+	h.applyOwnTimesheet(c, timesheet.ActionSubmit)
+}
+
+// ReopenTimesheet はログインしているユーザーの提出済み・差し戻しのタイムシートを下書きに戻します
+// 承認済みのタイムシートを下書きに戻せるのは承認者のみです
+func (h *Handler) ReopenTimesheet(c *gin.Context) {
+	h.applyOwnTimesheet(c, timesheet.ActionReopen)
+}
+
+func (h *Handler) applyOwnTimesheet(c *gin.Context, action timesheet.Action) {
+	if !h.timesheetsEnabled(c) {
+		return
+	}
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+	comment, ok := commentBody(c)
+	if !ok {
+		return
+	}
+	user := auth.UserName(c)
+
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	// 認証が無効の場合は承認者を区別しない
+	byApprover := h.auth == nil
+	sheet, err := h.timesheets.Apply(ctx, user, period, action, requestActor(c), comment, byApprover)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.respondTimesheet(ctx, c, http.StatusOK, sheet)
+}
+
+// GetTeamTimesheets はチームのメンバーのタイムシートの一覧を返します（state=submitted で承認待ち）
+func (h *Handler) GetTeamTimesheets(c *gin.Context) {
+	if !h.timesheetsEnabled(c) {
+		return
+	}
+	team, ok := h.viewableTeam(c)
+	if !ok {
+		return
+	}
+	state, ok := stateQuery(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	sheets, err := h.timesheets.List(ctx, team.Members, state)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sheets)
+}
+
+// GetTeamMemberTimesheet はチームのメンバーの期間のタイムシートを遷移の記録とともに返します
+func (h *Handler) GetTeamMemberTimesheet(c *gin.Context) {
+	if !h.timesheetsEnabled(c) {
+		return
+	}
+	member, ok := h.teamMember(c)
+	if !ok {
+		return
+	}
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Read)
+	defer cancel()
+	sheet, err := h.timesheets.Get(ctx, member, period)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.respondTimesheet(ctx, c, http.StatusOK, sheet)
+}
+
+// ApproveTimesheet はチームのメンバーの提出済みのタイムシートを承認します
+func (h *Handler) ApproveTimesheet(c *gin.Context) {
+	h.reviewTimesheet(c, timesheet.ActionApprove)
+}
+
+// RejectTimesheet はチームのメンバーの提出済みのタイムシートを理由（comment）を付けて差し戻します
+func (h *Handler) RejectTimesheet(c *gin.Context) {
+	h.reviewTimesheet(c, timesheet.ActionReject)
+}
+
+// ReopenMemberTimesheet はチームのメンバーのタイムシート（承認済みを含む）を下書きに戻します
+func (h *Handler) ReopenMemberTimesheet(c *gin.Context) {
+	h.reviewTimesheet(c, timesheet.ActionReopen)
+}
+
+// reviewTimesheet は承認者（チームを閲覧できるマネージャーまたは管理者）としてタイムシートの状態を変更します
+// 自分のタイムシートは承認者として操作できません
+func (h *Handler) reviewTimesheet(c *gin.Context, action timesheet.Action) {
+	if !h.timesheetsEnabled(c) {
+		return
+	}
+	member, ok := h.teamMember(c)
+	if !ok {
+		return
+	}
+	if member == auth.UserName(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "自分のタイムシートは承認・差し戻しできません"})
+		return
+	}
+	period, ok := periodParam(c)
+	if !ok {
+		return
+	}
+	comment, ok := commentBody(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := requestContext(c, h.timeouts.Write)
+	defer cancel()
+	sheet, err := h.timesheets.Apply(ctx, member, period, action, requestActor(c), comment, true)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.respondTimesheet(ctx, c, http.StatusOK, sheet)
+}
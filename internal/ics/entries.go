@@ -0,0 +1,158 @@
+package ics
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// untitledContent は件名のない予定の内容です
+const untitledContent = "予定"
+
+// idPrefix はカレンダーから取り込んだエントリのIDの接頭辞です
+const idPrefix = "ics-"
+
+// masterData は業務データベースの値を項目ごとにまとめたものです
+type masterData map[string][]string
+
+func newMasterData(items []models.DbItem) masterData {
+	m := make(masterData)
+	for _, item := range items {
+		key := models.NormalizeFieldKey(item.Type)
+		m[key] = append(m[key], item.Value)
+	}
+	return m
+}
+
+// exact は値が s と一致する（大文字と小文字は区別しない）登録済みの値を返します
+func (m masterData) exact(field, s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	for _, v := range m[field] {
+		if s != "" && strings.EqualFold(v, s) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// contained は s に含まれる登録済みの値のうち最も長いものを返します（1文字の値は誤りやすいため除きます）
+func (m masterData) contained(field, s string) string {
+	lower := strings.ToLower(s)
+	best := ""
+	for _, v := range m[field] {
+		if utf8.RuneCountInString(v) < 2 || !strings.Contains(lower, strings.ToLower(v)) {
+			continue
+		}
+		if utf8.RuneCountInString(v) > utf8.RuneCountInString(best) {
+			best = v
+		}
+	}
+	return best
+}
+
+// EntryID は予定の回から決まるエントリのIDです。同じ予定を再度取り込んだ場合の重複の判定に使用します
+func EntryID(e Event) string {
+	key := e.UID
+	if key == "" {
+		key = e.Summary
+	}
+	sum := sha1.Sum([]byte(key + "|" + e.Start.UTC().Format(time.RFC3339)))
+	return idPrefix + hex.EncodeToString(sum[:])[:12]
+}
+
+// ToEntry は予定をタイムエントリに変換します。時間は day（その日の0時）の範囲に切り詰めた時間帯です
+// 件名と参加者は業務データベースの値に対応付けられる場合はその値を使用します
+func ToEntry(e Event, day time.Time, items []models.DbItem) models.TimeEntry {
+	return toEntry(e, day, newMasterData(items))
+}
+
+func toEntry(e Event, day time.Time, master masterData) models.TimeEntry {
+	start, end := e.Start, e.End
+	if start.Before(day) {
+		start = day
+	}
+	if dayEnd := day.AddDate(0, 0, 1); end.After(dayEnd) {
+		end = dayEnd
+	}
+	loc := day.Location()
+
+	entry := models.TimeEntry{
+		ID:   EntryID(e),
+		Time: start.In(loc).Format("15:04") + " - " + end.In(loc).Format("15:04"),
+	}
+
+	summary := strings.TrimSpace(e.Summary)
+	entry.Content = summary
+	if v, ok := master.exact(models.FieldContent, summary); ok {
+		entry.Content = v
+	}
+	if entry.Content == "" {
+		entry.Content = untitledContent
+	}
+	entry.Client = master.contained(models.FieldClient, summary)
+	entry.Purpose = master.contained(models.FieldPurpose, summary)
+	entry.Action = master.contained(models.FieldAction, summary)
+
+	// 会議室などの設備と欠席者は除く
+	var names []string
+	var others []string
+	for _, a := range e.Attendees {
+		if a.Role == "ROOM" || a.Role == "RESOURCE" || a.PartStat == "DECLINED" {
+			continue
+		}
+		name := a.DisplayName()
+		if name == "" {
+			continue
+		}
+		names = append(names, name)
+		if entry.With == "" && len(master[models.FieldWith]) > 0 {
+			if v, ok := master.exact(models.FieldWith, name); ok {
+				entry.With = v
+				continue
+			}
+			if v, ok := master.exact(models.FieldWith, a.Email); ok {
+				entry.With = v
+				continue
+			}
+		}
+		others = append(others, name)
+	}
+	if len(master[models.FieldWith]) == 0 {
+		// 「誰と」の選択肢が登録されていない場合は参加者をそのまま記録する
+		entry.With = strings.Join(names, "、")
+	} else if len(others) > 0 {
+		// 選択肢にない参加者は検証で誤りにならないよう備考に記録する
+		entry.Remark = "参加者: " + strings.Join(others, "、")
+	}
+	return entry
+}
+
+// MergeDay は day に行われる予定を既存のエントリに追加します
+// 既存のエントリは変更せず、取り込み済みの予定（同じID）は追加しません
+// 追加するエントリは、時間帯を持つ既存のエントリのうち開始時刻が後のものの前に挿入します
+func MergeDay(existing []models.TimeEntry, events []Event, day time.Time, items []models.DbItem) (merged []models.TimeEntry, added []models.TimeEntry) {
+	master := newMasterData(items)
+	merged = append([]models.TimeEntry{}, existing...)
+	for _, e := range events {
+		entry := toEntry(e, day, master)
+		if models.FindEntry(merged, entry.ID) >= 0 {
+			continue
+		}
+		slot, _ := entry.Slot()
+
+		pos := len(merged)
+		for i, other := range merged {
+			if s, err := other.Slot(); err == nil && s.HasRange() && s.Start > slot.Start {
+				pos = i
+				break
+			}
+		}
+		merged = append(merged[:pos], append([]models.TimeEntry{entry}, merged[pos:]...)...)
+		added = append(added, entry)
+	}
+	return merged, added
+}
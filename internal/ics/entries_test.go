@@ -0,0 +1,95 @@
+package ics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+func TestMergeDay(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, jst)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 19, hour, minute, 0, 0, jst)
+	}
+	event := func(uid, summary string, start, end time.Time) Event {
+		return Event{UID: uid, Summary: summary, Start: start, End: end}
+	}
+
+	morning := event("m", "朝会", at(8, 30), at(9, 0))
+	lunch := event("l", "A社 定例", at(11, 0), at(12, 0))
+	late := event("n", "夜間作業", at(23, 0), at(25, 0)) // 翌日までの予定は当日の終わりまで
+	existing := []models.TimeEntry{
+		{ID: "e1", Time: "09:00 - 10:00", Content: "設計"},
+		{ID: "e2", Time: "30", Content: "メール"}, // 時間帯のないエントリは挿入位置の判定に使用しない
+		{ID: "e3", Time: "13:00 - 14:00", Content: "実装"},
+		{ID: EntryID(morning), Time: "08:30 - 09:00", Content: "朝会（編集済み）"},
+	}
+
+	tests := []struct {
+		name      string
+		existing  []models.TimeEntry
+		events    []Event
+		wantTimes []string // 結合後のエントリの時間の順序
+		wantAdded []string // 追加したエントリの内容
+	}{
+		{
+			name:      "空の日",
+			events:    []Event{lunch, morning},
+			wantTimes: []string{"08:30 - 09:00", "11:00 - 12:00"},
+			wantAdded: []string{"A社 定例", "朝会"},
+		},
+		{
+			name:      "開始時刻の順に挿入",
+			existing:  existing[:3],
+			events:    []Event{lunch, late},
+			wantTimes: []string{"09:00 - 10:00", "30", "11:00 - 12:00", "13:00 - 14:00", "23:00 - 00:00"},
+			wantAdded: []string{"A社 定例", "夜間作業"},
+		},
+		{
+			name:      "取り込み済みの予定は追加しない",
+			existing:  existing,
+			events:    []Event{morning, lunch},
+			wantTimes: []string{"09:00 - 10:00", "30", "11:00 - 12:00", "13:00 - 14:00", "08:30 - 09:00"},
+			wantAdded: []string{"A社 定例"},
+		},
+		{
+			name:      "予定がない",
+			existing:  existing[:1],
+			wantTimes: []string{"09:00 - 10:00"},
+		},
+	}
+
+	items := []models.DbItem{{Type: models.FieldClient, Value: "A社"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := append([]models.TimeEntry(nil), tt.existing...)
+			merged, added := MergeDay(tt.existing, tt.events, day, items)
+
+			var times, contents []string
+			for _, e := range merged {
+				times = append(times, e.Time)
+			}
+			for _, e := range added {
+				contents = append(contents, e.Content)
+			}
+			if !reflect.DeepEqual(times, tt.wantTimes) {
+				t.Errorf("結合後の時間 = %q, want %q", times, tt.wantTimes)
+			}
+			if !reflect.DeepEqual(contents, tt.wantAdded) {
+				t.Errorf("追加した内容 = %q, want %q", contents, tt.wantAdded)
+			}
+			if !reflect.DeepEqual(tt.existing, before) {
+				t.Errorf("既存のエントリが変更されました: %+v", tt.existing)
+			}
+		})
+	}
+
+	t.Run("業務データベースへの対応付け", func(t *testing.T) {
+		_, added := MergeDay(nil, []Event{lunch}, day, items)
+		if len(added) != 1 || added[0].Client != "A社" || added[0].ID != EntryID(lunch) {
+			t.Errorf("追加したエントリ = %+v", added)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// DefaultDayStart は時間帯のないエントリを並べ始める時刻（分）の既定値です
+const DefaultDayStart = 9 * 60
+
+// uidDomain はエントリの予定のUIDの末尾です
+const uidDomain = "@timeslice"
+
+// FeedOptions はエントリをカレンダーとして書き出す際の設定です
+type FeedOptions struct {
+	Name         string               // カレンダーの名前
+	Location     *time.Location       // エントリの時刻のタイムゾーン
+	DayStart     int                  // 時間帯のないエントリを並べ始める時刻（0時からの分）
+	CustomFields []models.CustomField // 説明に含めるカスタム項目
+}
+
+// WriteFeed は日付ごとのエントリをカレンダー（iCalendar）として書き出します
+//
+// 時間帯（09:00 - 09:30）のエントリはその時間帯に、所要時間のみのエントリは直前のエントリの後
+// （その日の最初のエントリの場合は DayStart）に配置します。時間を解釈できないエントリは含めません。
+// UID はエントリのIDと日付から決まるため、カレンダーアプリは再読み込みのたびに予定を更新します。
+func WriteFeed(w io.Writer, entriesByDate map[string][]models.TimeEntry, opts FeedOptions) error {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	out := &feedWriter{w: bufio.NewWriter(w)}
+
+	out.line("BEGIN:VCALENDAR")
+	out.line("VERSION:2.0")
+	out.line("PRODID:-//TimeSlice//TimeSlice//JA")
+	out.line("CALSCALE:GREGORIAN")
+	if opts.Name != "" {
+		out.line("X-WR-CALNAME:" + escapeText(opts.Name))
+	}
+
+	dates := make([]string, 0, len(entriesByDate))
+	for date := range entriesByDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	for _, date := range dates {
+		day, err := time.ParseInLocation("2006-01-02", date, loc)
+		if err != nil {
+			continue
+		}
+		cursor := opts.DayStart
+		for _, entry := range entriesByDate[date] {
+			slot, err := entry.Slot()
+			if err != nil || slot.Minutes <= 0 {
+				continue
+			}
+			start := cursor
+			if slot.HasRange() {
+				start = clockMinutes(slot.Start)
+			}
+			cursor = start + slot.Minutes
+
+			begin := day.Add(time.Duration(start) * time.Minute)
+			end := begin.Add(time.Duration(slot.Minutes) * time.Minute)
+			out.line("BEGIN:VEVENT")
+			out.line("UID:" + entry.ID + "-" + date + uidDomain)
+			out.line("DTSTAMP:" + stamp)
+			out.line("DTSTART:" + begin.UTC().Format("20060102T150405Z"))
+			out.line("DTEND:" + end.UTC().Format("20060102T150405Z"))
+			out.line("SUMMARY:" + escapeText(feedSummary(entry)))
+			if description := feedDescription(entry, opts.CustomFields); description != "" {
+				out.line("DESCRIPTION:" + escapeText(description))
+			}
+			out.line("TRANSP:TRANSPARENT") // 記録した時間で空き時間の表示を変えない
+			out.line("END:VEVENT")
+		}
+	}
+
+	out.line("END:VCALENDAR")
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+// feedSummary は予定の件名（内容とクライアント）を返します
+func feedSummary(entry models.TimeEntry) string {
+	if entry.Client == "" {
+		return entry.Content
+	}
+	return entry.Content + "（" + entry.Client + "）"
+}
+
+// feedDescription は予定の説明（件名以外の項目を「見出し: 値」で1行ずつ）を返します
+func feedDescription(entry models.TimeEntry, customFields []models.CustomField) string {
+	var lines []string
+	for _, f := range models.Fields {
+		switch f.Key {
+		case models.FieldTime, models.FieldContent, models.FieldClient:
+			continue
+		}
+		if v := f.Value(entry); v != "" {
+			lines = append(lines, f.Label+": "+v)
+		}
+	}
+	for _, f := range customFields {
+		if v, ok := entry.Custom[f.Key]; ok && v != nil {
+			lines = append(lines, fmt.Sprintf("%s: %v", f.DisplayLabel(), v))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// clockMinutes は HH:MM を0時からの分に変換します
+func clockMinutes(s string) int {
+	var h, m int
+	fmt.Sscanf(s, "%d:%d", &h, &m)
+	return h*60 + m
+}
+
+// escapeText は TEXT 型の値をエスケープします
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// feedWriter は行を CRLF で区切り、75オクテットを超える行を折り返して書き込みます
+type feedWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (f *feedWriter) line(s string) {
+	if f.err != nil {
+		return
+	}
+	// 折り返しの位置は UTF-8 の文字の途中にならないようにする
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if _, f.err = f.w.WriteString(s[:cut] + "\r\n "); f.err != nil {
+			return
+		}
+		s = s[cut:]
+		limit = 74 // 継続行は先頭の空白を含めて75オクテット
+	}
+	_, f.err = f.w.WriteString(s + "\r\n")
+}
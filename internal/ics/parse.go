@@ -0,0 +1,314 @@
+// Package ics はカレンダー（iCalendar, RFC 5545）の予定を読み込んでタイムエントリに変換し、
+// タイムエントリをカレンダーとして書き出します
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Attendee は予定の参加者です
+type Attendee struct {
+	Name     string // CN（表示名）
+	Email    string // mailto: の宛先
+	Role     string // CUTYPE（INDIVIDUAL, ROOM, RESOURCE など）
+	PartStat string // 出欠（ACCEPTED, DECLINED など）
+}
+
+// DisplayName は参加者の表示名を返します（表示名がない場合はメールアドレスの@より前）
+func (a Attendee) DisplayName() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	name, _, _ := strings.Cut(a.Email, "@")
+	return name
+}
+
+// Event はカレンダーの1つの予定（VEVENT）です
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Status      string // CANCELLED の予定は取り込みません
+	Start       time.Time
+	End         time.Time
+	AllDay      bool // 終日の予定（DTSTART が日付のみ）
+	Organizer   Attendee
+	Attendees   []Attendee
+
+	RRule   string      // 繰り返しの規則
+	ExDates []time.Time // 繰り返しから除く回の開始日時
+
+	// RecurrenceID は繰り返しの予定のうち特定の回を変更した予定の場合に、元の回の開始日時です
+	RecurrenceID time.Time
+}
+
+// property は1行のプロパティ（NAME;PARAM=VALUE:value）です
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse はカレンダーから予定を読み込みます
+// 日時のタイムゾーンが指定されていない場合（フローティング時刻）は loc として解釈します
+func Parse(r io.Reader, loc *time.Location) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var current *Event
+	var skip []string // VEVENT 内の VALARM など、読み飛ばすコンポーネント
+	for n, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("%d 行目: %v", n+1, err)
+		}
+
+		switch {
+		case prop.name == "BEGIN" && current == nil && strings.EqualFold(prop.value, "VEVENT"):
+			current = &Event{}
+		case prop.name == "BEGIN" && current != nil:
+			skip = append(skip, strings.ToUpper(prop.value))
+		case prop.name == "END" && len(skip) > 0:
+			skip = skip[:len(skip)-1]
+		case prop.name == "END" && current != nil && strings.EqualFold(prop.value, "VEVENT"):
+			if current.End.IsZero() {
+				current.End = current.Start
+				if current.AllDay {
+					current.End = current.Start.AddDate(0, 0, 1)
+				}
+			}
+			if !current.Start.IsZero() {
+				events = append(events, *current)
+			}
+			current = nil
+		case current != nil && len(skip) == 0:
+			if err := current.set(prop, loc); err != nil {
+				return nil, fmt.Errorf("%d 行目 %s: %v", n+1, prop.name, err)
+			}
+		}
+	}
+	return events, nil
+}
+
+// set はプロパティを予定に設定します。取り込みに使用しないプロパティは無視します
+func (e *Event) set(prop property, loc *time.Location) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		e.UID = prop.value
+	case "SUMMARY":
+		e.Summary = unescapeText(prop.value)
+	case "DESCRIPTION":
+		e.Description = unescapeText(prop.value)
+	case "STATUS":
+		e.Status = strings.ToUpper(prop.value)
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseDateTime(prop, loc)
+	case "DTEND":
+		e.End, _, err = parseDateTime(prop, loc)
+	case "DURATION":
+		var d time.Duration
+		if d, err = parseDuration(prop.value); err == nil {
+			e.End = e.Start.Add(d)
+		}
+	case "ORGANIZER":
+		e.Organizer = parseAttendee(prop)
+	case "ATTENDEE":
+		e.Attendees = append(e.Attendees, parseAttendee(prop))
+	case "RRULE":
+		e.RRule = prop.value
+	case "EXDATE":
+		for _, v := range strings.Split(prop.value, ",") {
+			t, _, err := parseDateTime(property{params: prop.params, value: v}, loc)
+			if err != nil {
+				return err
+			}
+			e.ExDates = append(e.ExDates, t)
+		}
+	case "RECURRENCE-ID":
+		e.RecurrenceID, _, err = parseDateTime(prop, loc)
+	}
+	return err
+}
+
+// unfold は折り返された行（空白またはタブで始まる行）を前の行につなげます
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("カレンダーの読み込みに失敗しました: %w", err)
+	}
+	if len(lines) == 0 || !strings.EqualFold(strings.TrimSpace(lines[0]), "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("iCalendar 形式ではありません（BEGIN:VCALENDAR で始まっていません）")
+	}
+	return lines, nil
+}
+
+// parseProperty は NAME;PARAM=VALUE;PARAM="VALUE":value の形式の行を解釈します
+func parseProperty(line string) (property, error) {
+	prop := property{params: make(map[string]string)}
+
+	// 値の区切りの : は引用符の外の最初の : です
+	inQuote := false
+	sep := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuote = !inQuote
+		} else if r == ':' && !inQuote {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 {
+		return prop, fmt.Errorf("プロパティの形式が正しくありません: %q", line)
+	}
+	prop.value = line[sep+1:]
+
+	parts := splitOutsideQuotes(line[:sep], ';')
+	prop.name = strings.ToUpper(parts[0])
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(part, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+func splitOutsideQuotes(s string, sep rune) []string {
+	var parts []string
+	inQuote := false
+	start := 0
+	for i, r := range s {
+		if r == '"' {
+			inQuote = !inQuote
+		} else if r == sep && !inQuote {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescapeText は TEXT 型の値のエスケープ（\n, \, など）を戻します
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// parseDateTime は DATE-TIME（20261017T090000Z など）または DATE（20261017）の値を解釈します
+// TZID が指定された場合はそのタイムゾーン、末尾が Z の場合は UTC、それ以外は loc として解釈します
+func parseDateTime(prop property, loc *time.Location) (t time.Time, allDay bool, err error) {
+	value := strings.TrimSpace(prop.value)
+	if tzid := prop.params["TZID"]; tzid != "" {
+		// Outlook などの独自のタイムゾーン名は解釈できないため loc とみなす
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	if prop.params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err = time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err = time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseDuration は期間（PT1H30M, P1D, -PT15M など）を解釈します
+func parseDuration(s string) (time.Duration, error) {
+	value := strings.TrimSpace(s)
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign = -1
+		value = value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	if !strings.HasPrefix(value, "P") {
+		return 0, fmt.Errorf("期間の形式が正しくありません: %q", s)
+	}
+	value = value[1:]
+
+	var d time.Duration
+	inTime := false
+	number := 0
+	digits := false
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			number = number*10 + int(r-'0')
+			digits = true
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+		if !digits {
+			return 0, fmt.Errorf("期間の形式が正しくありません: %q", s)
+		}
+		unit := map[rune]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+		if inTime {
+			unit = map[rune]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+		}
+		u, ok := unit[r]
+		if !ok {
+			return 0, fmt.Errorf("期間の形式が正しくありません: %q", s)
+		}
+		d += time.Duration(number) * u
+		number, digits = 0, false
+	}
+	if digits {
+		return 0, fmt.Errorf("期間の形式が正しくありません: %q", s)
+	}
+	return sign * d, nil
+}
+
+// parseAttendee は ATTENDEE / ORGANIZER のプロパティを解釈します
+func parseAttendee(prop property) Attendee {
+	email := prop.value
+	if len(email) >= 7 && strings.EqualFold(email[:7], "mailto:") {
+		email = email[7:]
+	}
+	return Attendee{
+		Name:     unescapeText(prop.params["CN"]),
+		Email:    email,
+		Role:     strings.ToUpper(prop.params["CUTYPE"]),
+		PartStat: strings.ToUpper(prop.params["PARTSTAT"]),
+	}
+}
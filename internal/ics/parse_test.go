@@ -0,0 +1,174 @@
+package ics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// jst はテストで使用するタイムゾーンです（タイムゾーンのデータベースに依存しないよう固定）
+var jst = time.FixedZone("JST", 9*60*60)
+
+// calendar は VEVENT などの行を VCALENDAR で囲んだカレンダーを返します
+func calendar(lines ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+}
+
+func mustParse(t *testing.T, lines ...string) []Event {
+	t.Helper()
+	events, err := Parse(strings.NewReader(calendar(lines...)), jst)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return events
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []Event
+	}{
+		{
+			name: "UTCの日時と参加者",
+			lines: []string{
+				"BEGIN:VEVENT",
+				"UID:a@example.com",
+				"SUMMARY:定例\\, 週次",
+				"DESCRIPTION:1行目\\n2行目",
+				"DTSTART:20261019T000000Z",
+				"DTEND:20261019T010000Z",
+				"ORGANIZER;CN=田中:mailto:tanaka@example.com",
+				`ATTENDEE;CN="佐藤: PM";PARTSTAT=ACCEPTED:mailto:sato@example.com`,
+				"ATTENDEE;CUTYPE=ROOM;CN=会議室A:mailto:room-a@example.com",
+				"END:VEVENT",
+			},
+			want: []Event{{
+				UID:         "a@example.com",
+				Summary:     "定例, 週次",
+				Description: "1行目\n2行目",
+				Start:       time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+				End:         time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC),
+				Organizer:   Attendee{Name: "田中", Email: "tanaka@example.com"},
+				Attendees: []Attendee{
+					{Name: "佐藤: PM", Email: "sato@example.com", PartStat: "ACCEPTED"},
+					{Name: "会議室A", Email: "room-a@example.com", Role: "ROOM"},
+				},
+			}},
+		},
+		{
+			name: "折り返しとフローティング時刻と期間",
+			lines: []string{
+				"BEGIN:VEVENT",
+				"UID:b",
+				"SUMMARY:長い",
+				" 件名",
+				"DTSTART:20261019T090000",
+				"DURATION:PT1H30M",
+				"END:VEVENT",
+			},
+			want: []Event{{
+				UID:     "b",
+				Summary: "長い件名",
+				Start:   time.Date(2026, 10, 19, 9, 0, 0, 0, jst),
+				End:     time.Date(2026, 10, 19, 10, 30, 0, 0, jst),
+			}},
+		},
+		{
+			name: "終日の予定と通知の読み飛ばし",
+			lines: []string{
+				"BEGIN:VEVENT",
+				"UID:c",
+				"SUMMARY:休暇",
+				"DTSTART;VALUE=DATE:20261019",
+				"BEGIN:VALARM",
+				"DESCRIPTION:通知",
+				"TRIGGER:-PT15M",
+				"END:VALARM",
+				"END:VEVENT",
+			},
+			want: []Event{{
+				UID:     "c",
+				Summary: "休暇",
+				Start:   time.Date(2026, 10, 19, 0, 0, 0, 0, jst),
+				End:     time.Date(2026, 10, 20, 0, 0, 0, 0, jst),
+				AllDay:  true,
+			}},
+		},
+		{
+			name: "繰り返しの除外と変更した回",
+			lines: []string{
+				"BEGIN:VEVENT",
+				"UID:d",
+				"DTSTART:20261005T090000",
+				"DTEND:20261005T093000",
+				"RRULE:FREQ=WEEKLY;BYDAY=MO",
+				"EXDATE:20261012T090000,20261026T090000",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:d",
+				"RECURRENCE-ID:20261019T090000",
+				"DTSTART:20261019T140000",
+				"DTEND:20261019T143000",
+				"STATUS:cancelled",
+				"END:VEVENT",
+			},
+			want: []Event{
+				{
+					UID:     "d",
+					Start:   time.Date(2026, 10, 5, 9, 0, 0, 0, jst),
+					End:     time.Date(2026, 10, 5, 9, 30, 0, 0, jst),
+					RRule:   "FREQ=WEEKLY;BYDAY=MO",
+					ExDates: []time.Time{time.Date(2026, 10, 12, 9, 0, 0, 0, jst), time.Date(2026, 10, 26, 9, 0, 0, 0, jst)},
+				},
+				{
+					UID:          "d",
+					Status:       "CANCELLED",
+					Start:        time.Date(2026, 10, 19, 14, 0, 0, 0, jst),
+					End:          time.Date(2026, 10, 19, 14, 30, 0, 0, jst),
+					RecurrenceID: time.Date(2026, 10, 19, 9, 0, 0, 0, jst),
+				},
+			},
+		},
+		{
+			name: "開始日時のない予定",
+			lines: []string{
+				"BEGIN:VEVENT",
+				"UID:e",
+				"SUMMARY:日時なし",
+				"END:VEVENT",
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mustParse(t, tt.lines...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "iCalendar ではない", body: "BEGIN:VEVENT\r\nEND:VEVENT\r\n"},
+		{name: "空", body: ""},
+		{name: "区切りのない行", body: calendar("BEGIN:VEVENT", "SUMMARY", "END:VEVENT")},
+		{name: "日時の誤り", body: calendar("BEGIN:VEVENT", "DTSTART:2026-10-19", "END:VEVENT")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if events, err := Parse(strings.NewReader(tt.body), jst); err == nil {
+				t.Errorf("Parse = %+v, want error", events)
+			}
+		})
+	}
+}
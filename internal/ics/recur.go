@@ -0,0 +1,270 @@
+package ics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPeriods は繰り返しを展開する周期の上限です（規則の誤りで終わらない場合に備える）
+const maxPeriods = 100000
+
+// rrule は繰り返しの規則（RRULE）のうち取り込みに対応している部分です
+// FREQ は DAILY, WEEKLY, MONTHLY, YEARLY、条件は INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY に対応します
+type rrule struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []weekdayNum
+	byMonthDay []int
+}
+
+// weekdayNum は BYDAY の1つの値（2TU なら n=2, day=火曜日）です
+type weekdayNum struct {
+	n   int
+	day time.Weekday
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRRule は繰り返しの規則を解釈します
+func parseRRule(s string, loc *time.Location) (rrule, error) {
+	r := rrule{interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.freq = strings.ToUpper(value)
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err == nil && r.interval < 1 {
+				err = fmt.Errorf("INTERVAL は1以上です")
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+		case "UNTIL":
+			r.until, _, err = parseDateTime(property{value: value}, loc)
+			if err == nil && len(value) == 8 {
+				r.until = r.until.AddDate(0, 0, 1).Add(-time.Second) // 日付のみの場合はその日の終わりまで
+			}
+		case "BYDAY":
+			for _, v := range strings.Split(value, ",") {
+				v = strings.ToUpper(strings.TrimSpace(v))
+				if len(v) < 2 {
+					return r, fmt.Errorf("BYDAY の値が正しくありません: %q", v)
+				}
+				day, ok := rruleWeekdays[v[len(v)-2:]]
+				if !ok {
+					return r, fmt.Errorf("BYDAY の値が正しくありません: %q", v)
+				}
+				n := 0
+				if prefix := v[:len(v)-2]; prefix != "" {
+					if n, err = strconv.Atoi(prefix); err != nil {
+						return r, fmt.Errorf("BYDAY の値が正しくありません: %q", v)
+					}
+				}
+				r.byDay = append(r.byDay, weekdayNum{n: n, day: day})
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				d, err := strconv.Atoi(strings.TrimSpace(v))
+				if err != nil {
+					return r, fmt.Errorf("BYMONTHDAY の値が正しくありません: %q", v)
+				}
+				r.byMonthDay = append(r.byMonthDay, d)
+			}
+		}
+		if err != nil {
+			return r, fmt.Errorf("繰り返しの規則 %s が正しくありません: %v", key, err)
+		}
+	}
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return r, fmt.Errorf("未対応の繰り返しです: FREQ=%s", r.freq)
+	}
+	return r, nil
+}
+
+// starts は dtstart から始まる繰り返しの各回の開始日時のうち、before より前のものを順に visit に渡します
+func (r rrule) starts(dtstart, before time.Time, visit func(time.Time)) {
+	count := 0
+	for k := 0; k < maxPeriods; k++ {
+		candidates, periodStart := r.period(dtstart, k*r.interval)
+		if periodStart.AddDate(0, 0, -7).After(before) {
+			return
+		}
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+		for _, c := range candidates {
+			if c.Before(dtstart) {
+				continue
+			}
+			if !r.until.IsZero() && c.After(r.until) {
+				return
+			}
+			count++
+			if r.count > 0 && count > r.count {
+				return
+			}
+			if !c.Before(before) {
+				return
+			}
+			visit(c)
+		}
+	}
+}
+
+// period は dtstart から offset 周期後の周期に含まれる回の候補と、その周期の開始日を返します
+func (r rrule) period(dtstart time.Time, offset int) ([]time.Time, time.Time) {
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+	}
+	var candidates []time.Time
+
+	switch r.freq {
+	case "DAILY":
+		day := dtstart.AddDate(0, 0, offset)
+		if len(r.byDay) == 0 || r.matchesWeekday(day.Weekday()) {
+			candidates = append(candidates, day)
+		}
+		return candidates, day
+
+	case "WEEKLY":
+		day := dtstart.AddDate(0, 0, 7*offset)
+		if len(r.byDay) == 0 {
+			return []time.Time{day}, day
+		}
+		// 週の始まりは月曜日（WKST=MO）
+		monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		for _, wd := range r.byDay {
+			candidates = append(candidates, monday.AddDate(0, 0, (int(wd.day)+6)%7))
+		}
+		return candidates, monday
+
+	case "MONTHLY":
+		first := at(dtstart.Year(), dtstart.Month()+time.Month(offset), 1)
+		last := first.AddDate(0, 1, -1).Day()
+		switch {
+		case len(r.byMonthDay) > 0:
+			for _, d := range r.byMonthDay {
+				if d < 0 {
+					d = last + 1 + d
+				}
+				if d >= 1 && d <= last {
+					candidates = append(candidates, at(first.Year(), first.Month(), d))
+				}
+			}
+		case len(r.byDay) > 0:
+			for _, wd := range r.byDay {
+				candidates = append(candidates, weekdaysInMonth(first, last, wd)...)
+			}
+		default:
+			// 31日などその月にない日の回は飛ばす
+			if dtstart.Day() <= last {
+				candidates = append(candidates, at(first.Year(), first.Month(), dtstart.Day()))
+			}
+		}
+		return candidates, first
+
+	default: // YEARLY
+		year := dtstart.Year() + offset
+		day := at(year, dtstart.Month(), dtstart.Day())
+		if day.Month() == dtstart.Month() {
+			candidates = append(candidates, day)
+		}
+		return candidates, at(year, time.January, 1)
+	}
+}
+
+func (r rrule) matchesWeekday(day time.Weekday) bool {
+	for _, wd := range r.byDay {
+		if wd.day == day {
+			return true
+		}
+	}
+	return false
+}
+
+// weekdaysInMonth は月（first は1日、last は末日）のうち wd に該当する日を返します
+// wd.n が正の場合は第n週、負の場合は最後から数えた週、0の場合はすべての週です
+func weekdaysInMonth(first time.Time, last int, wd weekdayNum) []time.Time {
+	var days []time.Time
+	for d := 1; d <= last; d++ {
+		day := first.AddDate(0, 0, d-1)
+		if day.Weekday() == wd.day {
+			days = append(days, day)
+		}
+	}
+	switch {
+	case wd.n > 0 && wd.n <= len(days):
+		return days[wd.n-1 : wd.n]
+	case wd.n < 0 && -wd.n <= len(days):
+		i := len(days) + wd.n
+		return days[i : i+1]
+	case wd.n == 0:
+		return days
+	}
+	return nil
+}
+
+// EventsOn は day（その日の0時）から1日の間に行われる予定を開始日時の順に返します
+// 繰り返しの予定は該当する回に展開し、終日の予定・キャンセルされた予定・所要時間のない予定は除きます
+func EventsOn(events []Event, day time.Time) ([]Event, error) {
+	dayEnd := day.AddDate(0, 0, 1)
+	overlaps := func(e Event) bool {
+		return e.Start.Before(dayEnd) && e.End.After(day) && e.End.After(e.Start)
+	}
+
+	// 特定の回を変更・キャンセルした予定は、元の繰り返しの回の代わりに扱う
+	overridden := make(map[string]bool)
+	for _, e := range events {
+		if !e.RecurrenceID.IsZero() {
+			overridden[e.UID+"|"+e.RecurrenceID.UTC().Format(time.RFC3339)] = true
+		}
+	}
+
+	var result []Event
+	for _, e := range events {
+		if e.AllDay || e.Status == "CANCELLED" {
+			continue
+		}
+		if e.RRule == "" || !e.RecurrenceID.IsZero() {
+			if overlaps(e) {
+				result = append(result, e)
+			}
+			continue
+		}
+
+		rule, err := parseRRule(e.RRule, e.Start.Location())
+		if err != nil {
+			return nil, fmt.Errorf("予定 %q: %v", e.Summary, err)
+		}
+		duration := e.End.Sub(e.Start)
+		excluded := make(map[int64]bool)
+		for _, ex := range e.ExDates {
+			excluded[ex.Unix()] = true
+		}
+		rule.starts(e.Start, dayEnd, func(start time.Time) {
+			if excluded[start.Unix()] || overridden[e.UID+"|"+start.UTC().Format(time.RFC3339)] {
+				return
+			}
+			occurrence := e
+			occurrence.Start = start
+			occurrence.End = start.Add(duration)
+			occurrence.RecurrenceID = start
+			if overlaps(occurrence) {
+				result = append(result, occurrence)
+			}
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Start.Before(result[j].Start) })
+	return result, nil
+}
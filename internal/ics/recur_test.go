@@ -0,0 +1,165 @@
+package ics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// vevent は UID・件名・開始・終了と追加の行から VEVENT の行を作成します（日時は JST のフローティング時刻）
+func vevent(uid, summary, start, end string, extra ...string) []string {
+	lines := []string{"BEGIN:VEVENT", "UID:" + uid, "SUMMARY:" + summary, "DTSTART:" + start, "DTEND:" + end}
+	lines = append(lines, extra...)
+	return append(lines, "END:VEVENT")
+}
+
+func TestEventsOn(t *testing.T) {
+	// 2026-10-19 は月曜日（10月の月曜日は 5, 12, 19, 26 日）
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, jst)
+
+	tests := []struct {
+		name   string
+		events [][]string
+		want   []string // 開始・終了時刻と件名
+	}{
+		{
+			name: "単発の予定",
+			events: [][]string{
+				vevent("a", "当日", "20261019T100000", "20261019T110000"),
+				vevent("b", "前日", "20261018T100000", "20261018T110000"),
+				vevent("c", "日付をまたぐ", "20261018T233000", "20261019T003000"),
+				vevent("d", "朝", "20261019T083000", "20261019T090000"),
+			},
+			want: []string{"10/18 23:30-00:30 日付をまたぐ", "08:30-09:00 朝", "10:00-11:00 当日"},
+		},
+		{
+			name: "取り込まない予定",
+			events: [][]string{
+				{"BEGIN:VEVENT", "UID:a", "SUMMARY:終日", "DTSTART;VALUE=DATE:20261019", "END:VEVENT"},
+				vevent("b", "キャンセル", "20261019T100000", "20261019T110000", "STATUS:CANCELLED"),
+				vevent("c", "所要時間なし", "20261019T100000", "20261019T100000"),
+				vevent("d", "翌日の0時から", "20261020T000000", "20261020T010000"),
+			},
+			want: nil,
+		},
+		{
+			name: "毎週（BYDAY）",
+			events: [][]string{
+				vevent("a", "定例", "20261005T090000", "20261005T093000", "RRULE:FREQ=WEEKLY;BYDAY=MO,WE"),
+			},
+			want: []string{"09:00-09:30 定例"},
+		},
+		{
+			name: "除外した回",
+			events: [][]string{
+				vevent("a", "定例", "20261005T090000", "20261005T093000", "RRULE:FREQ=WEEKLY", "EXDATE:20261019T090000"),
+			},
+			want: nil,
+		},
+		{
+			name: "回数（COUNT）と終了日（UNTIL）",
+			events: [][]string{
+				vevent("a", "2回", "20261005T090000", "20261005T093000", "RRULE:FREQ=WEEKLY;COUNT=2"),
+				vevent("b", "3回", "20261005T100000", "20261005T103000", "RRULE:FREQ=WEEKLY;COUNT=3"),
+				vevent("c", "前日まで", "20261005T110000", "20261005T113000", "RRULE:FREQ=DAILY;UNTIL=20261018"),
+				vevent("d", "当日まで", "20261005T120000", "20261005T123000", "RRULE:FREQ=DAILY;UNTIL=20261019"),
+			},
+			want: []string{"10:00-10:30 3回", "12:00-12:30 当日まで"},
+		},
+		{
+			name: "間隔（INTERVAL）",
+			events: [][]string{
+				vevent("a", "隔日", "20261017T090000", "20261017T100000", "RRULE:FREQ=DAILY;INTERVAL=2"),
+				vevent("b", "隔日（ずれ）", "20261018T090000", "20261018T100000", "RRULE:FREQ=DAILY;INTERVAL=2"),
+				vevent("c", "隔週", "20261005T130000", "20261005T140000", "RRULE:FREQ=WEEKLY;INTERVAL=2"),
+			},
+			want: []string{"09:00-10:00 隔日", "13:00-14:00 隔週"},
+		},
+		{
+			name: "毎月",
+			events: [][]string{
+				vevent("a", "第3月曜", "20260921T090000", "20260921T100000", "RRULE:FREQ=MONTHLY;BYDAY=3MO"),
+				vevent("b", "最終月曜", "20260928T100000", "20260928T110000", "RRULE:FREQ=MONTHLY;BYDAY=-1MO"),
+				vevent("c", "19日", "20260819T110000", "20260819T120000", "RRULE:FREQ=MONTHLY;BYMONTHDAY=19"),
+				vevent("d", "月末", "20260831T120000", "20260831T130000", "RRULE:FREQ=MONTHLY;BYMONTHDAY=-1"),
+				vevent("e", "同じ日", "20260419T130000", "20260419T140000", "RRULE:FREQ=MONTHLY"),
+			},
+			want: []string{"09:00-10:00 第3月曜", "11:00-12:00 19日", "13:00-14:00 同じ日"},
+		},
+		{
+			name: "毎年",
+			events: [][]string{
+				vevent("a", "記念日", "20201019T090000", "20201019T100000", "RRULE:FREQ=YEARLY"),
+			},
+			want: []string{"09:00-10:00 記念日"},
+		},
+		{
+			name: "変更した回",
+			events: [][]string{
+				vevent("a", "定例", "20261005T090000", "20261005T093000", "RRULE:FREQ=WEEKLY"),
+				vevent("a", "定例（午後に変更）", "20261019T140000", "20261019T143000", "RECURRENCE-ID:20261019T090000"),
+			},
+			want: []string{"14:00-14:30 定例（午後に変更）"},
+		},
+		{
+			name: "キャンセルした回",
+			events: [][]string{
+				vevent("a", "定例", "20261005T090000", "20261005T093000", "RRULE:FREQ=WEEKLY"),
+				vevent("a", "定例", "20261019T090000", "20261019T093000", "RECURRENCE-ID:20261019T090000", "STATUS:CANCELLED"),
+			},
+			want: nil,
+		},
+		{
+			name: "別の日の回を当日に移動",
+			events: [][]string{
+				vevent("a", "定例", "20261006T090000", "20261006T093000", "RRULE:FREQ=WEEKLY"),
+				vevent("a", "定例（前日に移動）", "20261019T160000", "20261019T163000", "RECURRENCE-ID:20261020T090000"),
+			},
+			want: []string{"16:00-16:30 定例（前日に移動）"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []string
+			for _, e := range tt.events {
+				lines = append(lines, e...)
+			}
+			events, err := EventsOn(mustParse(t, lines...), day)
+			if err != nil {
+				t.Fatalf("EventsOn: %v", err)
+			}
+			var got []string
+			for _, e := range events {
+				got = append(got, describeEvent(e, day))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EventsOn =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventsOnInvalidRule(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, jst)
+	for _, rule := range []string{"FREQ=HOURLY", "FREQ=DAILY;INTERVAL=0", "FREQ=WEEKLY;BYDAY=XX", "FREQ=MONTHLY;BYMONTHDAY=x"} {
+		t.Run(rule, func(t *testing.T) {
+			events := mustParse(t, vevent("a", "誤り", "20261005T090000", "20261005T093000", "RRULE:"+rule)...)
+			if _, err := EventsOn(events, day); err == nil {
+				t.Errorf("EventsOn(%s) succeeded, want error", rule)
+			}
+		})
+	}
+}
+
+// describeEvent は予定を「開始-終了 件名」の形式で表します（day 以外の日時には日付も付けます）
+func describeEvent(e Event, day time.Time) string {
+	format := func(t time.Time) string {
+		t = t.In(day.Location())
+		if t.Year() == day.Year() && t.YearDay() == day.YearDay() {
+			return t.Format("15:04")
+		}
+		return t.Format("1/2 15:04")
+	}
+	return format(e.Start) + "-" + format(e.End) + " " + e.Summary
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// カスタム項目の型
+const (
+	CustomText    = "text"    // 自由入力の文字列
+	CustomSelect  = "select"  // 業務データベースの選択肢から選ぶ文字列
+	CustomNumber  = "number"  // 数値
+	CustomBoolean = "boolean" // はい／いいえ
+)
+
+var customKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// CustomField は設定で追加するタイムエントリの項目です
+// 値は TimeEntry.Custom に Key をキーとして保存します
+type CustomField struct {
+	Key   string `yaml:"key" json:"key"`     // 英小文字・数字・_ のキー（例: project_code）
+	Label string `yaml:"label" json:"label"` // スプレッドシートの見出しや画面に表示する名前
+	Type  string `yaml:"type" json:"type"`   // text, select, number, boolean
+}
+
+// DisplayLabel は見出しを返します（未設定の場合はキー）
+func (f CustomField) DisplayLabel() string {
+	if f.Label != "" {
+		return f.Label
+	}
+	return f.Key
+}
+
+// Matches は name がこの項目のキーまたは見出しかどうかを判定します
+func (f CustomField) Matches(name string) bool {
+	name = normalizeFieldName(name)
+	return name == f.Key || name == normalizeFieldName(f.DisplayLabel())
+}
+
+// Check は項目の定義が正しいかどうかを検証します
+func (f CustomField) Check() error {
+	if !customKeyPattern.MatchString(f.Key) {
+		return fmt.Errorf("カスタム項目のキーが不正です: %q（英小文字で始まる英小文字・数字・_）", f.Key)
+	}
+	if _, ok := LookupField(f.Key); ok {
+		return fmt.Errorf("カスタム項目のキー %q は既存の項目と重複しています", f.Key)
+	}
+	switch f.Type {
+	case CustomText, CustomSelect, CustomNumber, CustomBoolean:
+	default:
+		return fmt.Errorf("カスタム項目 %s の型が不正です: %q（text, select, number, boolean）", f.Key, f.Type)
+	}
+	return nil
+}
+
+// Parse は値をこの項目の型に変換します。空の値の場合は nil を返します
+// スプレッドシートから読み込んだ文字列や、JSONの数値・真偽値のどちらも受け付けます
+func (f CustomField) Parse(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return nil, nil
+		}
+		switch f.Type {
+		case CustomNumber:
+			n, err := strconv.ParseFloat(strings.ReplaceAll(v, ",", ""), 64)
+			if err != nil {
+				return nil, fmt.Errorf("数値を指定してください: %q", v)
+			}
+			return n, nil
+		case CustomBoolean:
+			switch strings.ToLower(v) {
+			case "true", "1", "yes", "はい":
+				return true, nil
+			case "false", "0", "no", "いいえ":
+				return false, nil
+			}
+			return nil, fmt.Errorf("true または false を指定してください: %q", v)
+		}
+		return v, nil
+	case float64:
+		switch f.Type {
+		case CustomNumber:
+			return v, nil
+		case CustomBoolean:
+			return nil, fmt.Errorf("true または false を指定してください: %v", v)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		switch f.Type {
+		case CustomBoolean:
+			return v, nil
+		case CustomNumber:
+			return nil, fmt.Errorf("数値を指定してください: %v", v)
+		}
+		return strconv.FormatBool(v), nil
+	}
+	return nil, fmt.Errorf("値の形式が不正です: %v", value)
+}
+
+// NormalizeCustom はエントリのカスタム項目の値を各項目の型に変換し、空の値を取り除きます
+// 変換できない値や定義されていない項目はそのまま残します（検証でエラーとして報告されます）
+func NormalizeCustom(entry *TimeEntry, fields []CustomField) {
+	if len(entry.Custom) == 0 {
+		entry.Custom = nil
+		return
+	}
+	custom := make(map[string]interface{}, len(entry.Custom))
+	for key, value := range entry.Custom {
+		custom[key] = value
+		for _, f := range fields {
+			if f.Key != key {
+				continue
+			}
+			parsed, err := f.Parse(value)
+			if err != nil {
+				break
+			}
+			if parsed == nil {
+				delete(custom, key)
+			} else {
+				custom[key] = parsed
+			}
+		}
+	}
+	if len(custom) == 0 {
+		custom = nil
+	}
+	entry.Custom = custom
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NewEntryID はタイムエントリの新しいIDを生成します
+func NewEntryID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand が失敗するのは環境の異常のみ
+	}
+	return hex.EncodeToString(b)
+}
+
+// EnsureEntryIDs はIDが未設定または重複しているエントリに新しいIDを割り当てたコピーを返します
+func EnsureEntryIDs(entries []TimeEntry) []TimeEntry {
+	result := make([]TimeEntry, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		if entry.ID == "" || seen[entry.ID] {
+			entry.ID = NewEntryID()
+		}
+		seen[entry.ID] = true
+		result[i] = entry
+	}
+	return result
+}
+
+// FindEntry は id のエントリの位置を返します。見つからない場合は -1 を返します
+func FindEntry(entries []TimeEntry, id string) int {
+	for i, entry := range entries {
+		if entry.ID == id {
+			return i
+		}
+	}
+	return -1
+}
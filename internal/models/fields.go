@@ -0,0 +1,143 @@
+package models
+
+import "strings"
+
+// タイムエントリの項目のキー
+const (
+	FieldTime    = "time"
+	FieldContent = "content"
+	FieldClient  = "client"
+	FieldPurpose = "purpose"
+	FieldAction  = "action"
+	FieldWith    = "with"
+	FieldPcCc    = "pccc"
+	FieldRemark  = "remark"
+)
+
+// Field はタイムエントリの1つの項目の定義です
+type Field struct {
+	Key     string   // APIやデータベースで使用するキー
+	Label   string   // スプレッドシートの見出しや画面に表示する名前
+	Aliases []string // 古いキーや、スプレッドシートで使われていた別名
+	Column  int      // 日付シートの列の位置（0始まり、A列が0）
+	DbItem  bool     // 業務データベースで選択肢を管理する項目かどうか
+}
+
+// Fields はタイムエントリの項目の一覧です（日付シートの列の順）
+// 項目名の対応はすべてここで定義し、各所ではこの一覧を参照してください
+var Fields = []Field{
+	{Key: FieldTime, Label: "時間", Column: 0},
+	{Key: FieldContent, Label: "内容", Aliases: []string{"task"}, Column: 1, DbItem: true},
+	{Key: FieldClient, Label: "クライアント", Aliases: []string{"クライアント（誰に、誰のために）"}, Column: 2, DbItem: true},
+	{Key: FieldPurpose, Label: "目的", Column: 3, DbItem: true},
+	{Key: FieldAction, Label: "アクション", Aliases: []string{"function", "機能別"}, Column: 4, DbItem: true},
+	{Key: FieldWith, Label: "誰と", Aliases: []string{"mall", "モール別"}, Column: 5, DbItem: true},
+	{Key: FieldPcCc, Label: "PC/CC", Aliases: []string{"costtype", "コスト区分"}, Column: 6, DbItem: true},
+	{Key: FieldRemark, Label: "備考", Column: 7, DbItem: true},
+}
+
+// fieldIndex は正規化した名前（キー・見出し・別名）から項目の位置への対応です
+var fieldIndex = func() map[string]int {
+	index := make(map[string]int)
+	for i, f := range Fields {
+		index[normalizeFieldName(f.Key)] = i
+		index[normalizeFieldName(f.Label)] = i
+		for _, alias := range f.Aliases {
+			index[normalizeFieldName(alias)] = i
+		}
+	}
+	return index
+}()
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// LookupField はキー・見出し・別名のいずれかから項目を探します（大文字と小文字は区別しません）
+func LookupField(name string) (Field, bool) {
+	i, ok := fieldIndex[normalizeFieldName(name)]
+	if !ok {
+		return Field{}, false
+	}
+	return Fields[i], true
+}
+
+// NormalizeFieldKey は名前を項目のキーに変換します
+// 登録されていない名前は、前後の空白を除いて小文字にしたものを返します
+func NormalizeFieldKey(name string) string {
+	if f, ok := LookupField(name); ok {
+		return f.Key
+	}
+	return normalizeFieldName(name)
+}
+
+// DbItemFields は業務データベースで選択肢を管理する項目を順に返します
+func DbItemFields() []Field {
+	var fields []Field
+	for _, f := range Fields {
+		if f.DbItem {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Value はエントリからこの項目の値を取り出します
+func (f Field) Value(entry TimeEntry) string {
+	if p := f.ptr(&entry); p != nil {
+		return *p
+	}
+	return ""
+}
+
+// SetValue はエントリのこの項目に値を設定します
+func (f Field) SetValue(entry *TimeEntry, value string) {
+	if p := f.ptr(entry); p != nil {
+		*p = value
+	}
+}
+
+func (f Field) ptr(entry *TimeEntry) *string {
+	switch f.Key {
+	case FieldTime:
+		return &entry.Time
+	case FieldContent:
+		return &entry.Content
+	case FieldClient:
+		return &entry.Client
+	case FieldPurpose:
+		return &entry.Purpose
+	case FieldAction:
+		return &entry.Action
+	case FieldWith:
+		return &entry.With
+	case FieldPcCc:
+		return &entry.PcCc
+	case FieldRemark:
+		return &entry.Remark
+	}
+	return nil
+}
+
+// Add は key の項目の選択肢に値を追加します。業務データベースの項目でない場合は false を返します
+func (d *DbItems) Add(key, value string) bool {
+	switch key {
+	case FieldContent:
+		d.Content = append(d.Content, value)
+	case FieldClient:
+		d.Client = append(d.Client, value)
+	case FieldPurpose:
+		d.Purpose = append(d.Purpose, value)
+	case FieldAction:
+		d.Action = append(d.Action, value)
+	case FieldWith:
+		d.With = append(d.With, value)
+	case FieldPcCc:
+		d.PcCc = append(d.PcCc, value)
+	case FieldRemark:
+		d.Remark = append(d.Remark, value)
+	default:
+		return false
+	}
+	return true
+}
@@ -1,7 +1,10 @@
 package models
 
+import "time"
+
 // TimeEntry はタイムスライスのエントリを表します
 type TimeEntry struct {
+	ID      string `json:"id,omitempty"` // 日付の中で一意な永続ID（並べ替えや編集をしても変わらない）
 	Time    string `json:"time"`
 	Content string `json:"content"` // 内容
 	Client  string `json:"client"`  // クライアント（誰に、誰のために）
@@ -10,6 +13,9 @@ type TimeEntry struct {
 	With    string `json:"with"`    // 誰と
 	PcCc    string `json:"pccc"`    // PC/CC
 	Remark  string `json:"remark"`  // 備考
+
+	// Custom は設定で追加したカスタム項目の値です（キーは CustomField.Key）
+	Custom map[string]interface{} `json:"custom,omitempty"`
 }
 
 // DbItem は業務データベースの項目を表します
@@ -28,3 +34,10 @@ type DbItems struct {
 	PcCc    []string `json:"pccc"`    // PC/CC
 	Remark  []string `json:"remark"`  // 備考
 }
+
+// SyncState はローカルとスプレッドシートの日単位の同期状態を表します
+type SyncState struct {
+	Date     string    `json:"date"`
+	Hash     string    `json:"hash"`      // 最後に同期した時点のエントリのハッシュ
+	SyncedAt time.Time `json:"synced_at"` // 最後に同期した日時
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Preset はタイムエントリの入力を省略するためのひな形です
+// 項目は TimeEntry と同じで、ID はプリセットの中で一意な永続IDです
+type Preset struct {
+	TimeEntry
+	Name   string `json:"name"`
+	Shared bool   `json:"shared"`          // チーム全員に表示する場合は true（false の場合は所有者のみ）
+	Owner  string `json:"owner,omitempty"` // 作成した利用者
+
+	// Weekdays は繰り返しの曜日（mon〜sun）です
+	// 指定した曜日の日に最初のエントリを保存するとき、その日の先頭に自動で追加します
+	Weekdays []string `json:"weekdays,omitempty"`
+}
+
+// VisibleTo はプリセットが user に表示されるかどうかを判定します
+func (p Preset) VisibleTo(user string) bool {
+	return p.Shared || p.Owner == user
+}
+
+// RepeatsOn はプリセットが weekday に繰り返すテンプレートかどうかを判定します
+func (p Preset) RepeatsOn(weekday time.Weekday) bool {
+	for _, name := range p.Weekdays {
+		if d, err := ParseWeekday(name); err == nil && d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeWeekdays は繰り返しの曜日をキー（mon〜sun）に揃え、重複を除きます
+// 解釈できない表記はそのまま残します（検証でエラーとして報告されます）
+func (p *Preset) NormalizeWeekdays() {
+	var weekdays []string
+	seen := make(map[string]bool)
+	for _, name := range p.Weekdays {
+		if d, err := ParseWeekday(name); err == nil {
+			name = WeekdayKey(d)
+		}
+		if !seen[name] {
+			seen[name] = true
+			weekdays = append(weekdays, name)
+		}
+	}
+	p.Weekdays = weekdays
+}
+
+// Entry はプリセットから新しいIDのタイムエントリを作成します
+func (p Preset) Entry() TimeEntry {
+	entry := p.TimeEntry
+	entry.ID = NewEntryID()
+	if len(p.Custom) > 0 {
+		entry.Custom = make(map[string]interface{}, len(p.Custom))
+		for key, value := range p.Custom {
+			entry.Custom[key] = value
+		}
+	}
+	return entry
+}
+
+// FindPreset は id のプリセットの位置を返します。見つからない場合は -1 を返します
+func FindPreset(presets []Preset, id string) int {
+	for i, p := range presets {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// weekdayNames は曜日のキー（time.Weekday の順）です
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// weekdayAliases は曜日のキー以外に受け付ける表記です
+var weekdayAliases = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	"日": time.Sunday, "月": time.Monday, "火": time.Tuesday, "水": time.Wednesday,
+	"木": time.Thursday, "金": time.Friday, "土": time.Saturday,
+}
+
+// ParseWeekday は曜日の表記（mon, Monday, 月 など）を解釈します
+func ParseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for i, name := range weekdayNames {
+		if s == name {
+			return time.Weekday(i), nil
+		}
+	}
+	if d, ok := weekdayAliases[s]; ok {
+		return d, nil
+	}
+	return 0, fmt.Errorf("曜日を解釈できません: %q（mon〜sun）", s)
+}
+
+// WeekdayKey は曜日のキー（mon〜sun）を返します
+func WeekdayKey(d time.Weekday) string {
+	return weekdayNames[d]
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeSlot は TimeEntry.Time を解釈した時間枠を表します
+// Start/End は時間帯（"09:00 - 09:30"）で指定された場合のみ設定されます
+// 開始時刻のみ（"9:00"）の場合は Start だけを設定し、所要時間は 0 になります
+type TimeSlot struct {
+	Start   string `json:"start,omitempty"` // 開始時刻（HH:MM）
+	End     string `json:"end,omitempty"`   // 終了時刻（HH:MM）
+	Minutes int    `json:"duration_minutes"`
+}
+
+// HasRange は開始・終了時刻を持つかどうかを返します
+func (s TimeSlot) HasRange() bool {
+	return s.Start != "" && s.End != ""
+}
+
+// String は正規化された表記を返します
+// 時間帯の場合は "09:00 - 09:30"、開始時刻のみの場合は "09:00"、所要時間のみの場合は分（"30"）になります
+func (s TimeSlot) String() string {
+	if s.HasRange() {
+		return s.Start + " - " + s.End
+	}
+	if s.Start != "" {
+		return s.Start
+	}
+	return strconv.Itoa(s.Minutes)
+}
+
+// elapsedHoursLimit は "H:MM" を経過時間として扱う時の上限です
+// 時間帯でない "H:MM" は時がこれより小さい場合は経過時間（"1:30" は90分）、以上の場合は開始時刻（"9:00"）として扱います
+const elapsedHoursLimit = 6
+
+// rangeSeparators は時間帯の開始と終了の区切り文字です
+var rangeSeparators = []string{"〜", "～", "~", "–", "-"}
+
+// ParseTimeSlot は時間の文字列を解釈します
+// 対応形式:
+//   - 時間帯: "09:00 - 09:30", "9:00〜10:30"
+//   - 分: "30", "30分", "30m", "30min"
+//   - 時間: "1.5h", "1.5時間", "1h30m", "1時間30分"
+//   - 経過時間: "0:30", "1:30", "5:45"（時が elapsedHoursLimit より小さい [h]:mm 表記）
+//   - 開始時刻: "9:00", "13:30"（時が elapsedHoursLimit 以上の場合。所要時間は 0 になります）
+//
+// 単位のない数値は常に分として扱います（日の割合は ParseTimeSlotValue の数値のセルのみ）
+func ParseTimeSlot(s string) (TimeSlot, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return TimeSlot{}, fmt.Errorf("時間が入力されていません")
+	}
+
+	for _, sep := range rangeSeparators {
+		start, end, found := strings.Cut(s, sep)
+		if !found {
+			continue
+		}
+		startMin, err := parseClock(strings.TrimSpace(start))
+		if err != nil {
+			return TimeSlot{}, fmt.Errorf("開始時刻を解釈できません: %q", s)
+		}
+		endMin, err := parseClock(strings.TrimSpace(end))
+		if err != nil {
+			return TimeSlot{}, fmt.Errorf("終了時刻を解釈できません: %q", s)
+		}
+		minutes := endMin - startMin
+		if minutes < 0 {
+			minutes += 24 * 60 // 日付をまたぐ場合
+		}
+		return TimeSlot{
+			Start:   formatClock(startMin),
+			End:     formatClock(endMin),
+			Minutes: minutes,
+		}, nil
+	}
+
+	if strings.Contains(s, ":") {
+		minutes, err := parseClock(s)
+		if err != nil {
+			return TimeSlot{}, fmt.Errorf("時間を解釈できません: %q", s)
+		}
+		if minutes < elapsedHoursLimit*60 {
+			return TimeSlot{Minutes: minutes}, nil
+		}
+		return TimeSlot{Start: formatClock(minutes)}, nil
+	}
+
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return parseMinutes(v)
+	}
+
+	// 単位付きの表記は time.ParseDuration の形式に揃えて解釈する
+	unit := strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	unit = strings.NewReplacer("時間", "h", "分", "m", "mins", "m", "min", "m", "hours", "h", "hour", "h", "hrs", "h", "hr", "h").Replace(unit)
+	d, err := time.ParseDuration(unit)
+	if err != nil || d < 0 {
+		return TimeSlot{}, fmt.Errorf("時間を解釈できません: %q", s)
+	}
+	return TimeSlot{Minutes: int(math.Round(d.Minutes()))}, nil
+}
+
+// ParseTimeSlotValue はスプレッドシートのセル値（文字列または数値）を解釈します
+// 数値のセルのうち 0〜1 の小数はスプレッドシートの時間値（1日 = 1.0、"0.0208333" は30分）として扱います
+func ParseTimeSlotValue(v interface{}) (TimeSlot, error) {
+	switch val := v.(type) {
+	case nil:
+		return TimeSlot{}, fmt.Errorf("時間が入力されていません")
+	case float64:
+		if val > 0 && val < 1 {
+			return TimeSlot{Minutes: int(math.Round(val * 24 * 60))}, nil
+		}
+		return parseMinutes(val)
+	case int:
+		return parseMinutes(float64(val))
+	case int64:
+		return parseMinutes(float64(val))
+	case string:
+		return ParseTimeSlot(val)
+	default:
+		return ParseTimeSlot(fmt.Sprintf("%v", val))
+	}
+}
+
+// NormalizeTime は時間の文字列を正規化します（解釈できない場合はそのまま返します）
+func NormalizeTime(s string) string {
+	slot, err := ParseTimeSlot(s)
+	if err != nil {
+		return s
+	}
+	return slot.String()
+}
+
+// Slot はエントリの時間を解釈した結果を返します
+func (e TimeEntry) Slot() (TimeSlot, error) {
+	return ParseTimeSlot(e.Time)
+}
+
+// parseMinutes は数値を分として解釈します
+func parseMinutes(v float64) (TimeSlot, error) {
+	if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return TimeSlot{}, fmt.Errorf("時間を解釈できません: %v", v)
+	}
+	return TimeSlot{Minutes: int(math.Round(v))}, nil
+}
+
+// parseClock は "H:MM" 形式を分に変換します
+func parseClock(s string) (int, error) {
+	hours, minutes, found := strings.Cut(s, ":")
+	if !found {
+		return 0, fmt.Errorf("時刻の形式が正しくありません: %q", s)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil || h < 0 {
+		return 0, fmt.Errorf("時刻の形式が正しくありません: %q", s)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m >= 60 {
+		return 0, fmt.Errorf("時刻の形式が正しくありません: %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// formatClock は分を "HH:MM" 形式に変換します
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
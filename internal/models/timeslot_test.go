@@ -0,0 +1,95 @@
+package models
+
+import "testing"
+
+func TestParseTimeSlot(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    TimeSlot
+		str     string // 正規化された表記
+		wantErr bool
+	}{
+		{in: "09:00 - 09:30", want: TimeSlot{Start: "09:00", End: "09:30", Minutes: 30}, str: "09:00 - 09:30"},
+		{in: "9:00〜10:30", want: TimeSlot{Start: "09:00", End: "10:30", Minutes: 90}, str: "09:00 - 10:30"},
+		{in: "23:30-0:30", want: TimeSlot{Start: "23:30", End: "00:30", Minutes: 60}, str: "23:30 - 00:30"},
+		{in: "30", want: TimeSlot{Minutes: 30}, str: "30"},
+		{in: " 30分 ", want: TimeSlot{Minutes: 30}, str: "30"},
+		{in: "30min", want: TimeSlot{Minutes: 30}, str: "30"},
+		{in: "1.5h", want: TimeSlot{Minutes: 90}, str: "90"},
+		{in: "1時間30分", want: TimeSlot{Minutes: 90}, str: "90"},
+		{in: "1h30m", want: TimeSlot{Minutes: 90}, str: "90"},
+		// 文字列の小数は日の割合ではなく分として扱う
+		{in: "0.5", want: TimeSlot{Minutes: 1}, str: "1"},
+		{in: "45.4", want: TimeSlot{Minutes: 45}, str: "45"},
+		// 時が elapsedHoursLimit より小さい場合は経過時間、以上の場合は開始時刻
+		{in: "0:30", want: TimeSlot{Minutes: 30}, str: "30"},
+		{in: "1:30", want: TimeSlot{Minutes: 90}, str: "90"},
+		{in: "5:59", want: TimeSlot{Minutes: 359}, str: "359"},
+		{in: "6:00", want: TimeSlot{Start: "06:00"}, str: "06:00"},
+		{in: "9:00", want: TimeSlot{Start: "09:00"}, str: "09:00"},
+		{in: "13:45", want: TimeSlot{Start: "13:45"}, str: "13:45"},
+		{in: "", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "-30", wantErr: true},
+		{in: "9:75", wantErr: true},
+		{in: "9:00 - 終了", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseTimeSlot(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTimeSlot(%q) = %+v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTimeSlot(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTimeSlot(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if s := got.String(); s != tt.str {
+				t.Errorf("ParseTimeSlot(%q).String() = %q, want %q", tt.in, s, tt.str)
+			}
+		})
+	}
+}
+
+func TestParseTimeSlotValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      interface{}
+		want    TimeSlot
+		wantErr bool
+	}{
+		// 数値のセルの 0〜1 の小数はスプレッドシートの時間値（1日 = 1.0）
+		{name: "時間値", in: 0.0208333, want: TimeSlot{Minutes: 30}},
+		{name: "半日", in: 0.5, want: TimeSlot{Minutes: 720}},
+		{name: "数値の分", in: 45.0, want: TimeSlot{Minutes: 45}},
+		{name: "整数", in: 30, want: TimeSlot{Minutes: 30}},
+		{name: "文字列", in: "0.5", want: TimeSlot{Minutes: 1}},
+		{name: "文字列の時間帯", in: "09:00 - 09:30", want: TimeSlot{Start: "09:00", End: "09:30", Minutes: 30}},
+		{name: "空", in: nil, wantErr: true},
+		{name: "負の数", in: -1.0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimeSlotValue(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTimeSlotValue(%v) = %+v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTimeSlotValue(%v): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTimeSlotValue(%v) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// EntriesHash はエントリの内容からハッシュを計算します（時間は正規化して比較します）
+// IDは保存先ごとに採番される場合があるため含めず、内容と並び順のみを比較します
+// 保存先によらず同じ内容なら同じ値になるため、同期や楽観的排他制御のバージョンとして使用します
+func EntriesHash(entries []TimeEntry) string {
+	normalized := make([]TimeEntry, len(entries))
+	for i, entry := range entries {
+		entry.ID = ""
+		entry.Time = NormalizeTime(entry.Time)
+		normalized[i] = entry
+	}
+	data, _ := json.Marshal(normalized)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
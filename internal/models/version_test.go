@@ -0,0 +1,92 @@
+package models
+
+import "testing"
+
+func TestEntriesHash(t *testing.T) {
+	base := []TimeEntry{
+		{ID: "a", Time: "09:00 - 09:30", Content: "設計", Client: "A社"},
+		{ID: "b", Time: "30", Content: "会議", Custom: map[string]interface{}{"billable": true, "hours": 1.5}},
+	}
+
+	tests := []struct {
+		name    string
+		entries []TimeEntry
+		same    bool // base と同じハッシュになるか
+	}{
+		{
+			name: "IDが異なる",
+			entries: []TimeEntry{
+				{ID: "x", Time: "09:00 - 09:30", Content: "設計", Client: "A社"},
+				{Time: "30", Content: "会議", Custom: map[string]interface{}{"billable": true, "hours": 1.5}},
+			},
+			same: true,
+		},
+		{
+			name: "時間の表記が異なる",
+			entries: []TimeEntry{
+				{ID: "a", Time: "9:00〜9:30", Content: "設計", Client: "A社"},
+				{ID: "b", Time: "30分", Content: "会議", Custom: map[string]interface{}{"hours": 1.5, "billable": true}},
+			},
+			same: true,
+		},
+		{
+			name: "内容が異なる",
+			entries: []TimeEntry{
+				{ID: "a", Time: "09:00 - 09:30", Content: "設計", Client: "B社"},
+				{ID: "b", Time: "30", Content: "会議", Custom: map[string]interface{}{"billable": true, "hours": 1.5}},
+			},
+		},
+		{
+			name: "時間が異なる",
+			entries: []TimeEntry{
+				{ID: "a", Time: "09:00 - 10:00", Content: "設計", Client: "A社"},
+				{ID: "b", Time: "30", Content: "会議", Custom: map[string]interface{}{"billable": true, "hours": 1.5}},
+			},
+		},
+		{
+			name: "カスタム項目が異なる",
+			entries: []TimeEntry{
+				{ID: "a", Time: "09:00 - 09:30", Content: "設計", Client: "A社"},
+				{ID: "b", Time: "30", Content: "会議", Custom: map[string]interface{}{"billable": false, "hours": 1.5}},
+			},
+		},
+		{
+			name: "並び順が異なる",
+			entries: []TimeEntry{
+				{ID: "b", Time: "30", Content: "会議", Custom: map[string]interface{}{"billable": true, "hours": 1.5}},
+				{ID: "a", Time: "09:00 - 09:30", Content: "設計", Client: "A社"},
+			},
+		},
+		{
+			name:    "エントリが少ない",
+			entries: base[:1],
+		},
+	}
+
+	want := EntriesHash(base)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EntriesHash(tt.entries)
+			if (got == want) != tt.same {
+				t.Errorf("EntriesHash の一致 = %v, want %v", got == want, tt.same)
+			}
+		})
+	}
+
+	t.Run("空", func(t *testing.T) {
+		if EntriesHash(nil) != EntriesHash([]TimeEntry{}) {
+			t.Error("nil と空のエントリのハッシュが異なります")
+		}
+		if EntriesHash(nil) == want {
+			t.Error("空のエントリのハッシュがエントリのある日と一致しました")
+		}
+	})
+
+	t.Run("引数を変更しない", func(t *testing.T) {
+		entries := []TimeEntry{{ID: "a", Time: "30分", Content: "設計"}}
+		EntriesHash(entries)
+		if entries[0].ID != "a" || entries[0].Time != "30分" {
+			t.Errorf("エントリが変更されました: %+v", entries[0])
+		}
+	})
+}
@@ -0,0 +1,358 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// キュー項目の状態
+const (
+	StatusPending = "pending" // 送信待ち
+	StatusFailed  = "failed"  // 再試行しても成功しないエラーで送信を中止
+)
+
+// pollInterval は送信待ちがない場合に次に確認するまでの間隔です
+const pollInterval = 10 * time.Second
+
+// replayPolicy はキューの再送間隔（指数バックオフ）です
+// 試行回数の上限は設けず、接続が回復するまで再送を続けます
+var replayPolicy = repository.RetryPolicy{
+	InitialBackoff: 2 * time.Second,
+	MaxBackoff:     5 * time.Minute,
+}
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Item は送信待ちキューの1件（1日分の保存）を表します
+type Item struct {
+	ID            int64              `json:"id"`
+	User          string             `json:"user,omitempty"` // 保存したユーザー（認証が無効の場合は空）
+	Date          string             `json:"date"`
+	Entries       []models.TimeEntry `json:"entries,omitempty"` // 送信を中止した項目のみ（保存できなかった内容を確認するため）
+	EntryCount    int                `json:"entry_count"`
+	Status        string             `json:"status"`
+	Attempts      int                `json:"attempts"`
+	LastError     string             `json:"last_error,omitempty"`
+	CreatedAt     string             `json:"created_at"`
+	NextAttemptAt string             `json:"next_attempt_at"`
+}
+
+// Status はキュー全体の状態を表します
+type Status struct {
+	Pending       int    `json:"pending"`
+	Failed        int    `json:"failed"`
+	LastSuccessAt string `json:"last_success_at,omitempty"`
+	LastError     string `json:"last_error,omitempty"`
+	Items         []Item `json:"items"`
+}
+
+// Outbox はスプレッドシートへの保存をSQLiteに記録し、接続回復後に順番に再送します
+type Outbox struct {
+	db     *sql.DB
+	target repository.Repository
+	wake   chan struct{}
+
+	// 最後の再送の結果はユーザーごとに保持する（他のユーザーのエラーを返さない）
+	mu            sync.Mutex
+	lastSuccessAt map[string]time.Time
+	lastError     map[string]string
+}
+
+// Open はキューのデータベースを開きます（存在しない場合は作成します）
+func Open(path string, target repository.Repository) (*Outbox, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS outbox (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			date TEXT NOT NULL,
+			entries TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			next_attempt_at TEXT NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_outbox_status ON outbox (status, id);
+	`)
+	if err == nil {
+		err = addUserColumn(db)
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("送信待ちキューの作成に失敗しました: %w", err)
+	}
+
+	return &Outbox{
+		db:            db,
+		target:        target,
+		wake:          make(chan struct{}, 1),
+		lastSuccessAt: make(map[string]time.Time),
+		lastError:     make(map[string]string),
+	}, nil
+}
+
+// addUserColumn はユーザーの列がない（認証の導入前に作成された）キューに列を追加します
+func addUserColumn(db *sql.DB) error {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('outbox') WHERE name = 'user_id'`).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = db.Exec(`ALTER TABLE outbox ADD COLUMN user_id TEXT NOT NULL DEFAULT ''`)
+	return err
+}
+
+// SetTarget は再送先のリポジトリを変更します（Run の開始前に呼び出してください）
+func (o *Outbox) SetTarget(target repository.Repository) {
+	o.target = target
+}
+
+// Close はキューのデータベースを閉じます
+func (o *Outbox) Close() error {
+	return o.db.Close()
+}
+
+// Enqueue は user の1日分の保存をキューの末尾に追加します
+func (o *Outbox) Enqueue(user, date string, entries []models.TimeEntry, cause error) (int64, error) {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().Format(timeLayout)
+	lastError := ""
+	if cause != nil {
+		lastError = cause.Error()
+	}
+	res, err := o.db.Exec(`
+		INSERT INTO outbox (user_id, date, entries, status, last_error, created_at, next_attempt_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, user, date, string(data), StatusPending, lastError, now, now)
+	if err != nil {
+		return 0, fmt.Errorf("送信待ちキューへの登録に失敗しました: %w", err)
+	}
+
+	o.notify()
+	return res.LastInsertId()
+}
+
+// HasPendingFor は user の date に送信待ちの項目があるかどうかを返します
+func (o *Outbox) HasPendingFor(user, date string) (bool, error) {
+	var count int
+	err := o.db.QueryRow(`SELECT COUNT(*) FROM outbox WHERE status = ? AND user_id = ? AND date = ?`,
+		StatusPending, user, date).Scan(&count)
+	return count > 0, err
+}
+
+// PendingEntries は user の日付ごとに、送信待ちの最新のエントリを返します
+func (o *Outbox) PendingEntries(user string) (map[string][]models.TimeEntry, error) {
+	rows, err := o.db.Query(`
+		SELECT date, entries FROM outbox
+		WHERE status = ? AND user_id = ?
+		ORDER BY id
+	`, StatusPending, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]models.TimeEntry)
+	for rows.Next() {
+		var date, data string
+		if err := rows.Scan(&date, &data); err != nil {
+			return nil, err
+		}
+		var entries []models.TimeEntry
+		if err := json.Unmarshal([]byte(data), &entries); err != nil {
+			return nil, err
+		}
+		// 後から登録されたものが優先される
+		result[date] = entries
+	}
+	return result, rows.Err()
+}
+
+// Status は user の項目についてキューの状態を返します
+// 送信を中止した項目は読み込みに反映されないため、エラーと保存できなかったエントリを含めて返します
+func (o *Outbox) Status(user string) (*Status, error) {
+	rows, err := o.db.Query(`
+		SELECT id, user_id, date, entries, status, attempts, last_error, created_at, next_attempt_at
+		FROM outbox
+		WHERE user_id = ?
+		ORDER BY id
+	`, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	status := &Status{Items: []Item{}}
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		switch item.Status {
+		case StatusPending:
+			status.Pending++
+			item.Entries = nil
+		case StatusFailed:
+			status.Failed++
+		}
+		status.Items = append(status.Items, *item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	if at, ok := o.lastSuccessAt[user]; ok {
+		status.LastSuccessAt = at.Format(timeLayout)
+	}
+	status.LastError = o.lastError[user]
+	o.mu.Unlock()
+	return status, nil
+}
+
+// Run はキューの項目を古い順に送信します。ctx がキャンセルされるまで繰り返します
+// 一時的なエラーの場合は順序を保つため後続の項目も待機し、指数バックオフで再試行します
+// timeout は1件の送信にかける時間の上限です（0以下の場合は上限なし）
+func (o *Outbox) Run(ctx context.Context, timeout time.Duration) {
+	for {
+		wait := o.flush(ctx, timeout)
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.wake:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// flush は送信可能な項目を送信し、次に確認するまでの待ち時間を返します
+func (o *Outbox) flush(ctx context.Context, timeout time.Duration) time.Duration {
+	for {
+		item, err := o.head()
+		if err != nil {
+			log.Printf("送信待ちキューの読み込みに失敗しました: %v", err)
+			return pollInterval
+		}
+		if item == nil {
+			return pollInterval
+		}
+
+		next, _ := time.ParseInLocation(timeLayout, item.NextAttemptAt, time.Local)
+		if wait := time.Until(next); wait > 0 {
+			return wait
+		}
+
+		_, err = o.save(ctx, timeout, item)
+		if ctx.Err() != nil {
+			return pollInterval
+		}
+		if err == nil {
+			if _, err := o.db.Exec(`DELETE FROM outbox WHERE id = ?`, item.ID); err != nil {
+				log.Printf("送信済みの項目の削除に失敗しました (id=%d): %v", item.ID, err)
+				return pollInterval
+			}
+			o.mu.Lock()
+			o.lastSuccessAt[item.User] = time.Now()
+			delete(o.lastError, item.User)
+			o.mu.Unlock()
+			log.Printf("送信待ちの保存を反映しました: %s (id=%d)", item.Date, item.ID)
+			continue
+		}
+
+		o.mu.Lock()
+		o.lastError[item.User] = err.Error()
+		o.mu.Unlock()
+
+		if !repository.IsTemporary(err) {
+			// 再試行しても成功しないため、送信を中止して後続を処理する
+			log.Printf("送信待ちの保存に失敗したため中止します: %s (id=%d): %v", item.Date, item.ID, err)
+			_, uerr := o.db.Exec(`UPDATE outbox SET status = ?, attempts = attempts + 1, last_error = ? WHERE id = ?`,
+				StatusFailed, err.Error(), item.ID)
+			if uerr != nil {
+				log.Printf("送信待ちキューの更新に失敗しました (id=%d): %v", item.ID, uerr)
+				return pollInterval
+			}
+			continue
+		}
+
+		backoff := replayPolicy.Backoff(item.Attempts + 1)
+		log.Printf("送信待ちの保存に失敗しました。%v 後に再試行します: %s (id=%d): %v", backoff, item.Date, item.ID, err)
+		_, uerr := o.db.Exec(`UPDATE outbox SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?`,
+			err.Error(), time.Now().Add(backoff).Format(timeLayout), item.ID)
+		if uerr != nil {
+			log.Printf("送信待ちキューの更新に失敗しました (id=%d): %v", item.ID, uerr)
+		}
+		return backoff
+	}
+}
+
+// save は1件を送信します
+func (o *Outbox) save(ctx context.Context, timeout time.Duration, item *Item) (time.Time, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return o.target.SaveTimeEntries(repository.WithUser(ctx, item.User), item.Date, item.Entries)
+}
+
+// head は最も古い送信待ちの項目を返します（ない場合は nil）
+func (o *Outbox) head() (*Item, error) {
+	row := o.db.QueryRow(`
+		SELECT id, user_id, date, entries, status, attempts, last_error, created_at, next_attempt_at
+		FROM outbox
+		WHERE status = ?
+		ORDER BY id
+		LIMIT 1
+	`, StatusPending)
+	item, err := scanItem(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return item, err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanItem(s scanner) (*Item, error) {
+	var item Item
+	var data string
+	err := s.Scan(&item.ID, &item.User, &item.Date, &data, &item.Status, &item.Attempts,
+		&item.LastError, &item.CreatedAt, &item.NextAttemptAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(data), &item.Entries); err != nil {
+		return nil, err
+	}
+	item.EntryCount = len(item.Entries)
+	return &item, nil
+}
+
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,77 @@
+package outbox
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/repository"
+	"github.com/yourusername/timeslice-app/internal/timesheet"
+)
+
+func TestFlushSkipsLockedDays(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	local, err := repository.NewSQLiteRepository(filepath.Join(dir, "entries.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer local.Close()
+	store, err := timesheet.Open(filepath.Join(dir, "timesheets.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	queue, err := Open(filepath.Join(dir, "outbox.db"), local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Close()
+	queue.SetTarget(timesheet.NewRepository(local, store))
+
+	entries := []models.TimeEntry{{Time: "30", Content: "設計"}}
+	if _, err := queue.Enqueue("alice", "2026-10-14", entries, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queue.Enqueue("bob", "2026-10-14", entries, nil); err != nil {
+		t.Fatal(err)
+	}
+	// キューに登録した後に 2026-10-14 を含む週を提出する
+	week, _ := timesheet.ParsePeriod("2026-W42")
+	if _, err := store.Apply(ctx, "alice", week, timesheet.ActionSubmit, "alice", "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	queue.flush(ctx, 0)
+
+	status, err := queue.Status("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Pending != 0 || status.Failed != 1 || len(status.Items) != 1 {
+		t.Fatalf("キューの状態 = %+v", status)
+	}
+	if item := status.Items[0]; item.Date != "2026-10-14" || !strings.Contains(item.LastError, "2026-W42") || len(item.Entries) != 1 {
+		t.Errorf("送信を中止した項目 = %+v", item)
+	}
+
+	if !strings.Contains(status.LastError, "2026-W42") {
+		t.Errorf("最後のエラー = %q", status.LastError)
+	}
+	// 他のユーザーには再送のエラーを返さない
+	if other, err := queue.Status("bob"); err != nil || other.LastError != "" || other.LastSuccessAt == "" || len(other.Items) != 0 {
+		t.Errorf("他のユーザーのキューの状態 = %+v, %v", other, err)
+	}
+
+	for user, want := range map[string]int{"alice": 0, "bob": 1} {
+		saved, err := local.GetTimeEntries(repository.WithUser(ctx, user), "2026-10-14")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(saved) != want {
+			t.Errorf("%s のエントリ = %d 件, want %d", user, len(saved), want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// QueuedRepository はスプレッドシートへの保存が失敗した場合に送信待ちキューへ登録するリポジトリです
+// 送信待ちの日は読み込み時にキューの内容を返すため、保存直後の内容がすぐに参照できます
+type QueuedRepository struct {
+	repository.Repository
+	outbox *Outbox
+}
+
+func NewQueuedRepository(repo repository.Repository, outbox *Outbox) *QueuedRepository {
+	return &QueuedRepository{Repository: repo, outbox: outbox}
+}
+
+// SaveTimeEntries は同じ日の送信待ちがなければ直接保存し、一時的なエラーの場合はキューに登録します
+// キューに登録した場合は *repository.QueuedError を返します
+func (r *QueuedRepository) SaveTimeEntries(ctx context.Context, date string, entries []models.TimeEntry) (time.Time, error) {
+	user := repository.UserFromContext(ctx)
+	pending, err := r.outbox.HasPendingFor(user, date)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// 同じ日の先行する送信待ちがある場合は順序を保つためキューの末尾に追加する
+	if !pending {
+		updatedAt, err := r.Repository.SaveTimeEntries(ctx, date, entries)
+		if err == nil || !repository.IsTemporary(err) {
+			return updatedAt, err
+		}
+		id, qerr := r.outbox.Enqueue(user, date, entries, err)
+		if qerr != nil {
+			return time.Time{}, qerr
+		}
+		return time.Now(), &repository.QueuedError{ID: id, Cause: err}
+	}
+
+	id, err := r.outbox.Enqueue(user, date, entries, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now(), &repository.QueuedError{ID: id}
+}
+
+// GetTimeEntries は送信待ちの内容があればそれを返し、なければスプレッドシートから取得します
+func (r *QueuedRepository) GetTimeEntries(ctx context.Context, date string) ([]models.TimeEntry, error) {
+	pending, err := r.outbox.PendingEntries(repository.UserFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if entries, ok := pending[date]; ok {
+		return entries, nil
+	}
+	return r.Repository.GetTimeEntries(ctx, date)
+}
+
+// GetTimeEntriesRange はスプレッドシートの内容に送信待ちの内容を重ねて返します
+func (r *QueuedRepository) GetTimeEntriesRange(ctx context.Context, from, to string) (map[string][]models.TimeEntry, error) {
+	result, err := r.Repository.GetTimeEntriesRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := r.outbox.PendingEntries(repository.UserFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	for date, entries := range pending {
+		if date < from || date > to {
+			continue
+		}
+		if len(entries) == 0 {
+			delete(result, date)
+			continue
+		}
+		result[date] = entries
+	}
+	return result, nil
+}
@@ -0,0 +1,217 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// UnsetLabel は項目が未入力のエントリを集計する際のキーです
+const UnsetLabel = "(未設定)"
+
+// dimension は集計に使用できる項目を返します（業務データベースで管理する項目のうち備考以外）
+func dimension(name string) (models.Field, bool) {
+	field, ok := models.LookupField(name)
+	if !ok || !field.DbItem || field.Key == models.FieldRemark {
+		return models.Field{}, false
+	}
+	return field, true
+}
+
+// Bucket は1つの値（または値の組）に対する集計結果を表します
+type Bucket struct {
+	Key        string   `json:"key"`
+	Values     []string `json:"values,omitempty"` // 組み合わせ集計の場合の各項目の値
+	Minutes    int      `json:"minutes"`
+	Hours      float64  `json:"hours"`
+	Percentage float64  `json:"percentage"`
+	Count      int      `json:"count"`
+}
+
+// PairSummary は2項目の組み合わせごとの集計結果を表します
+type PairSummary struct {
+	Dimensions []string `json:"dimensions"`
+	Buckets    []Bucket `json:"buckets"`
+}
+
+// Summary は期間内のエントリの集計結果を表します
+type Summary struct {
+	From          string              `json:"from"`
+	To            string              `json:"to"`
+	GroupBy       []string            `json:"group_by"`
+	TotalMinutes  int                 `json:"total_minutes"`
+	TotalHours    float64             `json:"total_hours"`
+	EntryCount    int                 `json:"entry_count"`
+	UnparsedCount int                 `json:"unparsed_count"` // 時間を解釈できなかったエントリ数
+	Dimensions    map[string][]Bucket `json:"dimensions"`
+	Pairs         []PairSummary       `json:"pairs"`
+}
+
+// ParseGroupBy はカンマ区切りの集計項目を検証して返します（未指定の場合は client）
+func ParseGroupBy(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return []string{models.FieldClient}, nil
+	}
+
+	var groupBy []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		// 見出しや別名（クライアント など）で指定された場合もキーに揃える
+		field, ok := dimension(part)
+		if !ok {
+			return nil, fmt.Errorf("集計できない項目です: %s", part)
+		}
+		if seen[field.Key] {
+			continue
+		}
+		seen[field.Key] = true
+		groupBy = append(groupBy, field.Key)
+	}
+	return groupBy, nil
+}
+
+// Generate はリポジトリから期間内のエントリを取得して集計します
+func Generate(ctx context.Context, repo repository.Repository, from, to string, groupBy []string) (*Summary, error) {
+	entriesByDate, err := repo.GetTimeEntriesRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := Summarize(entriesByDate, groupBy)
+	summary.From = from
+	summary.To = to
+	return summary, nil
+}
+
+// accumulator は集計途中の値を保持します
+type accumulator struct {
+	values  []string
+	minutes int
+	count   int
+}
+
+// Summarize は日付ごとのエントリを項目別・項目の組み合わせ別に集計します
+func Summarize(entriesByDate map[string][]models.TimeEntry, groupBy []string) *Summary {
+	summary := &Summary{
+		GroupBy:    groupBy,
+		Dimensions: make(map[string][]Bucket),
+		Pairs:      []PairSummary{},
+	}
+
+	single := make(map[string]map[string]*accumulator)
+	for _, dim := range groupBy {
+		single[dim] = make(map[string]*accumulator)
+	}
+
+	type pairKey struct{ a, b string }
+	var pairs []pairKey
+	for i := 0; i < len(groupBy); i++ {
+		for j := i + 1; j < len(groupBy); j++ {
+			pairs = append(pairs, pairKey{groupBy[i], groupBy[j]})
+		}
+	}
+	pairAcc := make([]map[string]*accumulator, len(pairs))
+	for i := range pairs {
+		pairAcc[i] = make(map[string]*accumulator)
+	}
+
+	add := func(acc map[string]*accumulator, values []string, minutes int) {
+		key := strings.Join(values, " / ")
+		a, ok := acc[key]
+		if !ok {
+			a = &accumulator{values: values}
+			acc[key] = a
+		}
+		a.minutes += minutes
+		a.count++
+	}
+
+	for _, entries := range entriesByDate {
+		for _, entry := range entries {
+			summary.EntryCount++
+			slot, err := entry.Slot()
+			if err != nil {
+				summary.UnparsedCount++
+			}
+			minutes := slot.Minutes
+			summary.TotalMinutes += minutes
+
+			for _, dim := range groupBy {
+				add(single[dim], []string{dimensionValue(dim, entry)}, minutes)
+			}
+			for i, p := range pairs {
+				add(pairAcc[i], []string{dimensionValue(p.a, entry), dimensionValue(p.b, entry)}, minutes)
+			}
+		}
+	}
+
+	summary.TotalHours = toHours(summary.TotalMinutes)
+	for _, dim := range groupBy {
+		buckets := toBuckets(single[dim], summary.TotalMinutes)
+		// 単一項目の場合は values を省略する
+		for i := range buckets {
+			buckets[i].Values = nil
+		}
+		summary.Dimensions[dim] = buckets
+	}
+	for i, p := range pairs {
+		summary.Pairs = append(summary.Pairs, PairSummary{
+			Dimensions: []string{p.a, p.b},
+			Buckets:    toBuckets(pairAcc[i], summary.TotalMinutes),
+		})
+	}
+
+	return summary
+}
+
+// dimensionValue はエントリから集計項目の値を取り出します（空の場合は UnsetLabel）
+func dimensionValue(dim string, entry models.TimeEntry) string {
+	field, _ := dimension(dim)
+	value := strings.TrimSpace(field.Value(entry))
+	if value == "" {
+		return UnsetLabel
+	}
+	return value
+}
+
+// toBuckets は集計値を時間の多い順に並べた Bucket のスライスに変換します
+func toBuckets(acc map[string]*accumulator, totalMinutes int) []Bucket {
+	buckets := make([]Bucket, 0, len(acc))
+	for key, a := range acc {
+		bucket := Bucket{
+			Key:     key,
+			Values:  a.values,
+			Minutes: a.minutes,
+			Hours:   toHours(a.minutes),
+			Count:   a.count,
+		}
+		if totalMinutes > 0 {
+			bucket.Percentage = round1(float64(a.minutes) * 100 / float64(totalMinutes))
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Minutes != buckets[j].Minutes {
+			return buckets[i].Minutes > buckets[j].Minutes
+		}
+		return buckets[i].Key < buckets[j].Key
+	})
+	return buckets
+}
+
+func toHours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"context"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// MemberTotal はユーザー1人の期間内の合計です
+type MemberTotal struct {
+	User          string  `json:"user"`
+	TotalMinutes  int     `json:"total_minutes"`
+	TotalHours    float64 `json:"total_hours"`
+	EntryCount    int     `json:"entry_count"`
+	DaysLogged    int     `json:"days_logged"`    // エントリのある日数
+	UnparsedCount int     `json:"unparsed_count"` // 時間を解釈できなかったエントリ数
+}
+
+// TeamSummary はチームの期間内のエントリの集計結果を表します
+type TeamSummary struct {
+	Team    string        `json:"team"`
+	Members []MemberTotal `json:"members"`
+	*Summary
+}
+
+// Total は user の日付ごとのエントリの合計を返します
+func Total(user string, entriesByDate map[string][]models.TimeEntry) MemberTotal {
+	summary := Summarize(entriesByDate, nil)
+	total := MemberTotal{
+		User:          user,
+		TotalMinutes:  summary.TotalMinutes,
+		TotalHours:    summary.TotalHours,
+		EntryCount:    summary.EntryCount,
+		UnparsedCount: summary.UnparsedCount,
+	}
+	for _, entries := range entriesByDate {
+		if len(entries) > 0 {
+			total.DaysLogged++
+		}
+	}
+	return total
+}
+
+// GenerateTeam はメンバーごとにエントリを取得し、メンバー別の合計とチーム全体の集計を返します
+func GenerateTeam(ctx context.Context, repo repository.Repository, team string, members []string, from, to string, groupBy []string) (*TeamSummary, error) {
+	result := &TeamSummary{Team: team, Members: make([]MemberTotal, 0, len(members))}
+	combined := make(map[string][]models.TimeEntry)
+	for _, member := range members {
+		entriesByDate, err := repo.GetTimeEntriesRange(repository.WithUser(ctx, member), from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		for date, entries := range entriesByDate {
+			combined[date] = append(combined[date], entries...)
+		}
+		result.Members = append(result.Members, Total(member, entriesByDate))
+	}
+
+	result.Summary = Summarize(combined, groupBy)
+	result.From = from
+	result.To = to
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// Sheets APIのエラーの分類です。errors.Is で判定できます
+var (
+	ErrRateLimited      = errors.New("Google Sheets APIの利用上限に達しました")
+	ErrUnavailable      = errors.New("Google Sheets APIに接続できません")
+	ErrPermissionDenied = errors.New("スプレッドシートへのアクセス権限がありません")
+	ErrNotFound         = errors.New("スプレッドシートが見つかりません")
+)
+
+// APIError は再試行しても解決しなかったSheets APIのエラーを分類したものです
+type APIError struct {
+	Kind       error         // ErrRateLimited などの分類
+	RetryAfter time.Duration // サーバーから再試行までの待ち時間が指定された場合の値
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *APIError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// classifyAPIError はSheets APIのエラーを APIError に変換します（分類できない場合はそのまま返します）
+func classifyAPIError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		var kind error
+		switch apiErr.Code {
+		case http.StatusTooManyRequests:
+			kind = ErrRateLimited
+		case http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			kind = ErrUnavailable
+		case http.StatusUnauthorized, http.StatusForbidden:
+			kind = ErrPermissionDenied
+		case http.StatusNotFound:
+			kind = ErrNotFound
+		default:
+			return err
+		}
+		retryAfter, _ := parseRetryAfter(apiErr.Header.Get("Retry-After"))
+		return &APIError{Kind: kind, RetryAfter: retryAfter, Err: err}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return &APIError{Kind: ErrUnavailable, Err: err}
+	}
+	return err
+}
+
+// QueuedError は保存がすぐには反映されず、送信待ちキューに登録されたことを表します
+// 保存自体は受け付けられているため、呼び出し側は失敗ではなく「キュー登録済み」として扱います
+type QueuedError struct {
+	ID    int64 // キュー上のID
+	Cause error // 直接書き込めなかった理由（先行する送信待ちがある場合は nil）
+}
+
+func (e *QueuedError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("送信待ちキューに登録しました (id=%d): %v", e.ID, e.Cause)
+	}
+	return fmt.Sprintf("送信待ちキューに登録しました (id=%d)", e.ID)
+}
+
+func (e *QueuedError) Unwrap() error {
+	return e.Cause
+}
+
+// IsTemporary は通信障害やレート制限など、時間をおいて再試行すれば成功しうるエラーかどうかを判定します
+func IsTemporary(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable) {
+		return true
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
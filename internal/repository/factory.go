@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/timeslice-app/internal/config"
+)
+
+// NewRepository は設定で選択されたバックエンドのリポジトリを作成します
+func NewRepository(ctx context.Context, cfg *config.Config) (Repository, error) {
+	switch cfg.Backend {
+	case config.BackendSQLite:
+		repo, err := NewSQLiteRepository(cfg.SQLite.Path)
+		if err != nil {
+			return nil, err
+		}
+		return repo, nil
+	case config.BackendSheets:
+		repo, err := NewSheetsRepositoryFromConfig(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return repo, nil
+	default:
+		return nil, fmt.Errorf("未対応のバックエンドです: %s", cfg.Backend)
+	}
+}
+
+// NewSheetsRepositoryFromConfig は設定からスプレッドシートのリポジトリを作成します
+// 同期のように backend の設定によらずスプレッドシートが必要な場合にも使用します
+func NewSheetsRepositoryFromConfig(ctx context.Context, cfg *config.Config) (*SheetsRepository, error) {
+	repo, err := NewSheetsRepository(ctx, cfg.Sheets.CredentialsFile, cfg.Sheets.SpreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+	repo.DbItemsSheet = cfg.Sheets.DbItemsSheet
+	repo.PresetsSheet = cfg.Sheets.PresetsSheet
+	repo.CustomFields = cfg.CustomFields
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = cfg.Sheets.Retry.MaxAttempts
+	policy.MaxElapsed = cfg.Sheets.Retry.MaxElapsed
+	repo.SetRetryPolicy(policy)
+	return repo, nil
+}
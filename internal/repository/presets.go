@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// GetPresets はすべての利用者のプリセットを保存した順に返します
+func (r *SQLiteRepository) GetPresets(ctx context.Context) ([]models.Preset, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT preset_id, name, time, content, client, purpose, action, with_whom, pccc, remark, custom, shared, owner, weekdays
+		FROM presets
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	presets := []models.Preset{}
+	for rows.Next() {
+		var p models.Preset
+		var custom, weekdays string
+		err := rows.Scan(
+			&p.ID,
+			&p.Name,
+			&p.Time,
+			&p.Content,
+			&p.Client,
+			&p.Purpose,
+			&p.Action,
+			&p.With,
+			&p.PcCc,
+			&p.Remark,
+			&custom,
+			&p.Shared,
+			&p.Owner,
+			&weekdays,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if p.Custom, err = decodeCustom(custom); err != nil {
+			return nil, err
+		}
+		p.Weekdays = splitWeekdays(weekdays)
+		presets = append(presets, p)
+	}
+	return presets, rows.Err()
+}
+
+// SavePresets はプリセットを presets の内容と順序に置き換えます
+func (r *SQLiteRepository) SavePresets(ctx context.Context, presets []models.Preset) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// 既存のプリセットを削除
+	_, err = tx.ExecContext(ctx, "DELETE FROM presets")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO presets (preset_id, name, time, content, client, purpose, action, with_whom, pccc, remark, custom, shared, owner, weekdays, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now().Format(sqliteTimeLayout)
+	for _, p := range presets {
+		custom, err := encodeCustom(p.Custom)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		_, err = stmt.ExecContext(ctx,
+			p.ID,
+			p.Name,
+			models.NormalizeTime(p.Time),
+			p.Content,
+			p.Client,
+			p.Purpose,
+			p.Action,
+			p.With,
+			p.PcCc,
+			p.Remark,
+			custom,
+			p.Shared,
+			p.Owner,
+			strings.Join(p.Weekdays, ","),
+			now,
+		)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// splitWeekdays はカンマ区切りの曜日を分割します
+func splitWeekdays(s string) []string {
+	var weekdays []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			weekdays = append(weekdays, name)
+		}
+	}
+	return weekdays
+}
@@ -1,21 +1,33 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/yourusername/timeslice-app/internal/models"
 )
 
+// Repository はタイムエントリと業務データベースの保存先を表します
+// すべての操作は ctx のキャンセルや期限に従って中断されます
+// タイムエントリは ctx のユーザー（WithUser）ごとに分かれ、業務データベースとプリセットは全員で共有します
 type Repository interface {
-	GetTimeEntries(date string) ([]models.TimeEntry, error)
-	SaveTimeEntries(date string, entries []models.TimeEntry) (time.Time, error)
-	GetDbItems() ([]models.DbItem, error)
-	SaveDbItems(items []models.DbItem) error
-	DeleteDbItems(items []models.DbItem) error
+	GetTimeEntries(ctx context.Context, date string) ([]models.TimeEntry, error)
+	GetTimeEntriesRange(ctx context.Context, from, to string) (map[string][]models.TimeEntry, error)
+	SaveTimeEntries(ctx context.Context, date string, entries []models.TimeEntry) (time.Time, error)
+	GetDbItems(ctx context.Context) ([]models.DbItem, error)
+	SaveDbItems(ctx context.Context, items []models.DbItem) error
+	DeleteDbItems(ctx context.Context, items []models.DbItem) error
+	GetPresets(ctx context.Context) ([]models.Preset, error)
+	SavePresets(ctx context.Context, presets []models.Preset) error
 }
 
+// sqliteTimeLayout は updated_at などの日時カラムの書式です
+const sqliteTimeLayout = "2006-01-02 15:04:05"
+
 // SQLiteRepository はSQLiteデータベースを使用するリポジトリの実装
 type SQLiteRepository struct {
 	db *sql.DB
@@ -26,43 +38,155 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 	if err != nil {
 		return nil, err
 	}
+	// SQLiteは同時書き込みができないため接続を1本に制限する
+	db.SetMaxOpenConns(1)
 
-	// テーブル作成
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS time_entries (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			date TEXT NOT NULL,
-			time TEXT NOT NULL,
-			content TEXT,
-			client TEXT,
-			purpose TEXT,
-			action TEXT,
-			with_whom TEXT,
-			pccc TEXT,
-			remark TEXT,
-			updated_at TEXT NOT NULL
-		);
-		
-		CREATE TABLE IF NOT EXISTS db_items (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			type TEXT NOT NULL,
-			value TEXT NOT NULL
-		);
-	`)
-	if err != nil {
-		return nil, err
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("スキーマの作成に失敗しました: %v", err)
 	}
 
 	return &SQLiteRepository{db: db}, nil
 }
 
-func (r *SQLiteRepository) GetTimeEntries(date string) ([]models.TimeEntry, error) {
-	rows, err := r.db.Query(`
-		SELECT time, content, client, purpose, action, with_whom, pccc, remark
+// migrations はスキーマの変更履歴です。PRAGMA user_version に適用済みの件数を記録します
+// 既存の項目は変更せず、変更が必要な場合は末尾に追加してください
+var migrations = []string{
+	// 1: 初期スキーマ
+	`
+	CREATE TABLE IF NOT EXISTS time_entries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		date TEXT NOT NULL,
+		time TEXT NOT NULL,
+		content TEXT,
+		client TEXT,
+		purpose TEXT,
+		action TEXT,
+		with_whom TEXT,
+		pccc TEXT,
+		remark TEXT,
+		updated_at TEXT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_time_entries_date ON time_entries (date);
+
+	CREATE TABLE IF NOT EXISTS db_items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		type TEXT NOT NULL,
+		value TEXT NOT NULL
+	);
+	`,
+	// 2: 業務データベースの重複を除去して一意制約を追加
+	`
+	DELETE FROM db_items
+	WHERE id NOT IN (SELECT MIN(id) FROM db_items GROUP BY type, value);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_db_items_type_value ON db_items (type, value);
+	`,
+	// 3: スプレッドシートとの同期状態
+	`
+	CREATE TABLE IF NOT EXISTS sync_state (
+		date TEXT PRIMARY KEY,
+		hash TEXT NOT NULL,
+		synced_at TEXT NOT NULL
+	);
+	`,
+	// 4: エントリの永続ID（既存の行には新しいIDを割り当てる）
+	`
+	ALTER TABLE time_entries ADD COLUMN entry_id TEXT;
+
+	UPDATE time_entries SET entry_id = lower(hex(randomblob(8))) WHERE entry_id IS NULL;
+	`,
+	// 5: カスタム項目の値（JSON）
+	`
+	ALTER TABLE time_entries ADD COLUMN custom TEXT NOT NULL DEFAULT '';
+	`,
+	// 6: プリセット
+	`
+	CREATE TABLE IF NOT EXISTS presets (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		preset_id TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		time TEXT NOT NULL,
+		content TEXT NOT NULL,
+		client TEXT NOT NULL,
+		purpose TEXT NOT NULL,
+		action TEXT NOT NULL,
+		with_whom TEXT NOT NULL,
+		pccc TEXT NOT NULL,
+		remark TEXT NOT NULL,
+		custom TEXT NOT NULL DEFAULT '',
+		shared INTEGER NOT NULL DEFAULT 0,
+		owner TEXT NOT NULL DEFAULT '',
+		updated_at TEXT NOT NULL
+	);
+	`,
+	// 7: プリセットの繰り返しの曜日（カンマ区切り）
+	`
+	ALTER TABLE presets ADD COLUMN weekdays TEXT NOT NULL DEFAULT '';
+	`,
+	// 8: ユーザーごとのエントリと同期状態（既存のデータは共通のデータ（user_id = ''）とする）
+	`
+	ALTER TABLE time_entries ADD COLUMN user_id TEXT NOT NULL DEFAULT '';
+
+	CREATE INDEX IF NOT EXISTS idx_time_entries_user_date ON time_entries (user_id, date);
+
+	CREATE TABLE sync_state_v8 (
+		user_id TEXT NOT NULL DEFAULT '',
+		date TEXT NOT NULL,
+		hash TEXT NOT NULL,
+		synced_at TEXT NOT NULL,
+		PRIMARY KEY (user_id, date)
+	);
+
+	INSERT INTO sync_state_v8 (date, hash, synced_at) SELECT date, hash, synced_at FROM sync_state;
+
+	DROP TABLE sync_state;
+
+	ALTER TABLE sync_state_v8 RENAME TO sync_state;
+	`,
+}
+
+// migrate は未適用のマイグレーションを順に適用します
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("マイグレーション %d: %v", i+1, err)
+		}
+		// PRAGMAはプレースホルダを使用できないため値を埋め込む
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close はデータベース接続を閉じます
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteRepository) GetTimeEntries(ctx context.Context, date string) ([]models.TimeEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT entry_id, time, content, client, purpose, action, with_whom, pccc, remark, custom
 		FROM time_entries
-		WHERE date = ?
-		ORDER BY time
-	`, date)
+		WHERE user_id = ? AND date = ?
+		ORDER BY id -- 保存した順序（スプレッドシートの行順と同じ）
+	`, UserFromContext(ctx), date)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +195,9 @@ func (r *SQLiteRepository) GetTimeEntries(date string) ([]models.TimeEntry, erro
 	var entries []models.TimeEntry
 	for rows.Next() {
 		var entry models.TimeEntry
+		var custom string
 		err := rows.Scan(
+			&entry.ID,
 			&entry.Time,
 			&entry.Content,
 			&entry.Client,
@@ -80,33 +206,80 @@ func (r *SQLiteRepository) GetTimeEntries(date string) ([]models.TimeEntry, erro
 			&entry.With,
 			&entry.PcCc,
 			&entry.Remark,
+			&custom,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if entry.Custom, err = decodeCustom(custom); err != nil {
+			return nil, err
+		}
 		entries = append(entries, entry)
 	}
 
 	return entries, nil
 }
 
-func (r *SQLiteRepository) SaveTimeEntries(date string, entries []models.TimeEntry) (time.Time, error) {
-	tx, err := r.db.Begin()
+// GetTimeEntriesRange は from〜to（両端を含む）のエントリを日付ごとにまとめて返します
+func (r *SQLiteRepository) GetTimeEntriesRange(ctx context.Context, from, to string) (map[string][]models.TimeEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT date, entry_id, time, content, client, purpose, action, with_whom, pccc, remark, custom
+		FROM time_entries
+		WHERE user_id = ? AND date BETWEEN ? AND ?
+		ORDER BY date, id
+	`, UserFromContext(ctx), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]models.TimeEntry)
+	for rows.Next() {
+		var date, custom string
+		var entry models.TimeEntry
+		err := rows.Scan(
+			&date,
+			&entry.ID,
+			&entry.Time,
+			&entry.Content,
+			&entry.Client,
+			&entry.Purpose,
+			&entry.Action,
+			&entry.With,
+			&entry.PcCc,
+			&entry.Remark,
+			&custom,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Custom, err = decodeCustom(custom); err != nil {
+			return nil, err
+		}
+		result[date] = append(result[date], entry)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *SQLiteRepository) SaveTimeEntries(ctx context.Context, date string, entries []models.TimeEntry) (time.Time, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return time.Time{}, err
 	}
 
 	// 既存のエントリを削除
-	_, err = tx.Exec("DELETE FROM time_entries WHERE date = ?", date)
+	user := UserFromContext(ctx)
+	_, err = tx.ExecContext(ctx, "DELETE FROM time_entries WHERE user_id = ? AND date = ?", user, date)
 	if err != nil {
 		tx.Rollback()
 		return time.Time{}, err
 	}
 
 	// 新しいエントリを追加
-	stmt, err := tx.Prepare(`
-		INSERT INTO time_entries (date, time, content, client, purpose, action, with_whom, pccc, remark, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO time_entries (user_id, date, entry_id, time, content, client, purpose, action, with_whom, pccc, remark, custom, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		tx.Rollback()
@@ -115,11 +288,18 @@ func (r *SQLiteRepository) SaveTimeEntries(date string, entries []models.TimeEnt
 	defer stmt.Close()
 
 	now := time.Now()
-	formattedNow := now.Format("2006-01-02 15:04:05")
-	for _, entry := range entries {
-		_, err := stmt.Exec(
+	formattedNow := now.Format(sqliteTimeLayout)
+	for _, entry := range models.EnsureEntryIDs(entries) {
+		custom, err := encodeCustom(entry.Custom)
+		if err != nil {
+			tx.Rollback()
+			return time.Time{}, err
+		}
+		_, err = stmt.ExecContext(ctx,
+			user,
 			date,
-			entry.Time,
+			entry.ID,
+			models.NormalizeTime(entry.Time),
 			entry.Content,
 			entry.Client,
 			entry.Purpose,
@@ -127,6 +307,7 @@ func (r *SQLiteRepository) SaveTimeEntries(date string, entries []models.TimeEnt
 			entry.With,
 			entry.PcCc,
 			entry.Remark,
+			custom,
 			formattedNow,
 		)
 		if err != nil {
@@ -142,8 +323,29 @@ func (r *SQLiteRepository) SaveTimeEntries(date string, entries []models.TimeEnt
 	return now, nil
 }
 
-func (r *SQLiteRepository) GetDbItems() ([]models.DbItem, error) {
-	rows, err := r.db.Query(`
+// encodeCustom はカスタム項目の値をJSONに変換します（値がない場合は空文字）
+func encodeCustom(custom map[string]interface{}) (string, error) {
+	if len(custom) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(custom)
+	return string(data), err
+}
+
+// decodeCustom は custom カラムのJSONをカスタム項目の値に変換します
+func decodeCustom(data string) (map[string]interface{}, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var custom map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &custom); err != nil {
+		return nil, fmt.Errorf("カスタム項目の値を読み込めません: %v", err)
+	}
+	return custom, nil
+}
+
+func (r *SQLiteRepository) GetDbItems(ctx context.Context) ([]models.DbItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
 		SELECT id, type, value
 		FROM db_items
 		ORDER BY type, value
@@ -161,30 +363,21 @@ func (r *SQLiteRepository) GetDbItems() ([]models.DbItem, error) {
 			return nil, err
 		}
 		// 古いフィールド名を新しいフィールド名にマッピング
-		switch item.Type {
-		case "task":
-			item.Type = "content"
-		case "function":
-			item.Type = "action"
-		case "mall":
-			item.Type = "with"
-		case "costtype":
-			item.Type = "pccc"
-		}
+		item.Type = models.NormalizeFieldKey(item.Type)
 		items = append(items, item)
 	}
 
 	return items, nil
 }
 
-func (r *SQLiteRepository) SaveDbItems(items []models.DbItem) error {
-	tx, err := r.db.Begin()
+func (r *SQLiteRepository) SaveDbItems(ctx context.Context, items []models.DbItem) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
 	// 既存のアイテムを削除
-	_, err = tx.Exec("DELETE FROM db_items")
+	_, err = tx.ExecContext(ctx, "DELETE FROM db_items")
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -192,10 +385,11 @@ func (r *SQLiteRepository) SaveDbItems(items []models.DbItem) error {
 
 	// 新しいアイテムを追加
 	for _, item := range items {
-		_, err = tx.Exec(`
-			INSERT INTO db_items (type, value)
+		// 同じ種別・値の重複は無視する
+		_, err = tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO db_items (type, value)
 			VALUES (?, ?)
-		`, item.Type, item.Value)
+		`, models.NormalizeFieldKey(item.Type), item.Value)
 		if err != nil {
 			tx.Rollback()
 			return err
@@ -205,22 +399,48 @@ func (r *SQLiteRepository) SaveDbItems(items []models.DbItem) error {
 	return tx.Commit()
 }
 
-func (r *SQLiteRepository) DeleteDbItems(items []models.DbItem) error {
-	tx, err := r.db.Begin()
+func (r *SQLiteRepository) DeleteDbItems(ctx context.Context, items []models.DbItem) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
 	for _, item := range items {
-		_, err = tx.Exec(`
-			DELETE FROM db_items
-			WHERE type = ? AND value = ?
-		`, item.Type, item.Value)
+		// 古いフィールド名で保存された行も削除するため、種別は正規化して比較する
+		ids, err := matchingDbItemIDs(ctx, tx, models.NormalizeFieldKey(item.Type), item.Value)
 		if err != nil {
 			tx.Rollback()
 			return err
 		}
+		for _, id := range ids {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM db_items WHERE id = ?`, id); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
 	}
 
 	return tx.Commit()
 }
+
+// matchingDbItemIDs は正規化した種別が itemType で値が value の業務データベースの行の ID を返します
+func matchingDbItemIDs(ctx context.Context, tx *sql.Tx, itemType, value string) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id, type FROM db_items WHERE value = ?`, value)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var t string
+		if err := rows.Scan(&id, &t); err != nil {
+			return nil, err
+		}
+		if models.NormalizeFieldKey(t) == itemType {
+			ids = append(ids, id)
+		}
+	}
+	return ids, rows.Err()
+}
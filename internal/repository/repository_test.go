@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+func TestSQLiteDeleteDbItemsNormalizesType(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// 古いフィールド名・見出しで保存された行
+	for _, row := range [][2]string{{"機能別", "企画"}, {"クライアント", "A社"}, {models.FieldClient, "B社"}} {
+		if _, err := r.db.Exec(`INSERT INTO db_items (type, value) VALUES (?, ?)`, row[0], row[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = r.DeleteDbItems(ctx, []models.DbItem{
+		{Type: models.FieldAction, Value: "企画"},
+		{Type: "クライアント", Value: "B社"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := r.GetDbItems(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := stripDbItemIDs(items)
+	want := []models.DbItem{{Type: models.FieldClient, Value: "A社"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("削除後の項目 = %v, want %v", got, want)
+	}
+}
+
+func TestSQLiteSaveTimeEntriesNormalizesTime(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	entries := []models.TimeEntry{
+		{Time: "9:00〜9:30", Content: "朝会"},
+		{Time: "1:30", Content: "設計"},
+		{Time: "1.5h", Content: "実装"},
+		{Time: "未定", Content: "解釈できない時間"},
+	}
+	if _, err := r.SaveTimeEntries(ctx, "2026-10-19", entries); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := r.GetTimeEntries(ctx, "2026-10-19")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range saved {
+		got = append(got, e.Time)
+	}
+	want := []string{"09:00 - 09:30", "90", "90", "未定"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("保存した時間 = %q, want %q", got, want)
+	}
+}
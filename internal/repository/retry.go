@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"errors"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy はGoogle Sheets API呼び出しの再試行の条件です
+type RetryPolicy struct {
+	MaxAttempts    int           // 最初の呼び出しを含む最大試行回数
+	InitialBackoff time.Duration // 1回目の再試行までの待ち時間
+	MaxBackoff     time.Duration // 1回あたりの待ち時間の上限
+	MaxElapsed     time.Duration // 再試行を含めた合計時間の上限
+}
+
+// DefaultRetryPolicy は既定の再試行の条件を返します
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     16 * time.Second,
+		MaxElapsed:     30 * time.Second,
+	}
+}
+
+// Backoff は試行回数（1始まり）に応じたジッター付きの指数バックオフの待ち時間を返します
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	// 0.5〜1.0倍のジッター
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// retryTransport はレート制限（429）や一時的なサーバーエラー（5xx）の場合に
+// Retry-After ヘッダーまたは指数バックオフに従ってリクエストを再送します
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 {
+			// 本文を再送できないリクエストは再試行しない
+			if req.Body != nil && req.Body != http.NoBody {
+				if req.GetBody == nil {
+					return nil, errors.New("再送できないリクエストです")
+				}
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r = req.Clone(req.Context())
+				r.Body = body
+			}
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		if attempt >= t.policy.MaxAttempts || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.policy.Backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+		}
+		if time.Since(start)+wait > t.policy.MaxElapsed {
+			return resp, err
+		}
+
+		if resp != nil {
+			log.Printf("Sheets APIが %d を返しました。%v 後に再試行します (%d/%d): %s %s",
+				resp.StatusCode, wait, attempt, t.policy.MaxAttempts, req.Method, req.URL.Path)
+			// 接続を再利用できるよう本文を読み捨てる
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		} else {
+			log.Printf("Sheets APIへの接続に失敗しました。%v 後に再試行します (%d/%d): %v",
+				wait, attempt, t.policy.MaxAttempts, err)
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// shouldRetry は再試行すべき応答かどうかを判定します
+// POST（シートの追加など）は重複して実行されないよう、処理されていないことが明らかな場合のみ再試行します
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// 接続できなかった場合はリクエストが送信されていないため POST も再試行する
+		return req.Context().Err() == nil && (req.Method != http.MethodPost || isDialError(err))
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return req.Method != http.MethodPost
+	}
+	return false
+}
+
+// isDialError は接続の確立（名前解決を含む）に失敗したエラーかどうかを判定します
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// parseRetryAfter は Retry-After ヘッダー（秒数またはHTTP日付）を解釈します
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestShouldRetry(t *testing.T) {
+	dialErr := &url.Error{Op: "Post", URL: "https://sheets.googleapis.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	resetErr := &url.Error{Op: "Post", URL: "https://sheets.googleapis.com", Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}}
+
+	tests := []struct {
+		name   string
+		method string
+		status int
+		err    error
+		want   bool
+	}{
+		{name: "GET 429", method: http.MethodGet, status: http.StatusTooManyRequests, want: true},
+		{name: "POST 429", method: http.MethodPost, status: http.StatusTooManyRequests, want: true},
+		{name: "POST 503", method: http.MethodPost, status: http.StatusServiceUnavailable, want: true},
+		{name: "GET 500", method: http.MethodGet, status: http.StatusInternalServerError, want: true},
+		{name: "POST 500", method: http.MethodPost, status: http.StatusInternalServerError, want: false},
+		{name: "GET 400", method: http.MethodGet, status: http.StatusBadRequest, want: false},
+		{name: "GET 切断", method: http.MethodGet, err: resetErr, want: true},
+		{name: "GET EOF", method: http.MethodGet, err: io.ErrUnexpectedEOF, want: true},
+		// 送信後に切断された POST は処理された可能性があるため再試行しない
+		{name: "POST 切断", method: http.MethodPost, err: resetErr, want: false},
+		{name: "POST EOF", method: http.MethodPost, err: io.ErrUnexpectedEOF, want: false},
+		{name: "POST 接続できない", method: http.MethodPost, err: dialErr, want: true},
+		{name: "POST 名前解決できない", method: http.MethodPost, err: &net.DNSError{Err: "no such host", Name: "sheets.googleapis.com"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "https://sheets.googleapis.com/v4/spreadsheets", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status}
+			}
+			if got := shouldRetry(req, resp, tt.err); got != tt.want {
+				t.Errorf("shouldRetry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("キャンセル済み", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://sheets.googleapis.com/v4/spreadsheets", nil)
+		if shouldRetry(req, nil, dialErr) {
+			t.Error("キャンセルされたリクエストを再試行しました")
+		}
+	})
+}
@@ -16,42 +16,69 @@ import (
 // SheetsRepository はGoogle Sheetsを使用するリポジトリの実装
 type SheetsRepository struct {
 	Service       *sheetsv4.Service
+	DbItemsSheet  string               // 業務データベースのシート名
+	PresetsSheet  string               // プリセットのシート名
+	CustomFields  []models.CustomField // ID列の後に並べるカスタム項目
 	spreadsheetID string
+	retry         *retryTransport
 }
 
+// DefaultDbItemsSheet は業務データベースの既定のシート名です
+const DefaultDbItemsSheet = "業務データベース"
+
 func NewSheetsRepository(ctx context.Context, credentialsFile string, spreadsheetID string) (*SheetsRepository, error) {
 	// 認証情報を読み込む
 	credentials, err := os.ReadFile(credentialsFile)
 	if err != nil {
-		return nil, fmt.Errorf("認証情報の読み込みに失敗しました: %v", err)
+		return nil, fmt.Errorf("認証情報の読み込みに失敗しました: %w", err)
 	}
 
 	// 認証情報を使用して設定を作成（読み書き可能なスコープに変更）
 	config, err := google.JWTConfigFromJSON(credentials, sheetsv4.SpreadsheetsScope)
 	if err != nil {
-		return nil, fmt.Errorf("認証設定の作成に失敗しました: %v", err)
+		return nil, fmt.Errorf("認証設定の作成に失敗しました: %w", err)
 	}
 
-	// サービスを作成
+	// サービスを作成（レート制限や一時的なエラーは再試行する）
 	client := config.Client(ctx)
+	retry := &retryTransport{base: client.Transport, policy: DefaultRetryPolicy()}
+	client.Transport = retry
 	service, err := sheetsv4.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
-		return nil, fmt.Errorf("Sheetsサービスの作成に失敗しました: %v", err)
+		return nil, fmt.Errorf("Sheetsサービスの作成に失敗しました: %w", err)
 	}
 
+	repo := NewSheetsRepositoryWithService(service, spreadsheetID)
+	repo.retry = retry
+	return repo, nil
+}
+
+// NewSheetsRepositoryWithService は作成済みのSheetsサービスを使用するリポジトリを作成します
+// 認証や接続先を独自に設定したい場合（テスト用の偽サーバーなど）に使用します
+func NewSheetsRepositoryWithService(service *sheetsv4.Service, spreadsheetID string) *SheetsRepository {
 	return &SheetsRepository{
 		Service:       service,
+		DbItemsSheet:  DefaultDbItemsSheet,
+		PresetsSheet:  DefaultPresetsSheet,
 		spreadsheetID: spreadsheetID,
-	}, nil
+	}
 }
 
-func (r *SheetsRepository) GetTimeEntries(date string) ([]models.TimeEntry, error) {
-	// 日付をシート名として使用
-	rangeStr := date + "!A2:H"
+// SetRetryPolicy はAPI呼び出しの再試行の条件を変更します（起動時に設定してください）
+func (r *SheetsRepository) SetRetryPolicy(policy RetryPolicy) {
+	if r.retry == nil {
+		return // NewSheetsRepositoryWithService で作成した場合は再試行しない
+	}
+	r.retry.policy = policy
+}
+
+func (r *SheetsRepository) GetTimeEntries(ctx context.Context, date string) ([]models.TimeEntry, error) {
+	// 日付（ユーザーがある場合は「ユーザー/日付」）をシート名として使用
+	rangeStr := r.entryRange(entrySheetTitle(ctx, date))
 	fmt.Printf("スプレッドシートからデータを取得します: ID=%s, Range=%s\n", r.spreadsheetID, rangeStr)
 
 	// まずスプレッドシートのすべてのシート名を取得して確認
-	sheets, err := r.Service.Spreadsheets.Get(r.spreadsheetID).Do()
+	sheets, err := r.Service.Spreadsheets.Get(r.spreadsheetID).Context(ctx).Do()
 	if err != nil {
 		fmt.Printf("スプレッドシート情報の取得に失敗しました: %v\n", err)
 	} else {
@@ -61,7 +88,7 @@ func (r *SheetsRepository) GetTimeEntries(date string) ([]models.TimeEntry, erro
 		}
 	}
 
-	resp, err := r.Service.Spreadsheets.Values.Get(r.spreadsheetID, rangeStr).Do()
+	resp, err := r.Service.Spreadsheets.Values.Get(r.spreadsheetID, rangeStr).Context(ctx).Do()
 	if err != nil {
 		// より詳細なエラー情報を出力
 		fmt.Printf("タイムエントリ取得でエラーが発生しました: %v\n", err)
@@ -72,16 +99,135 @@ func (r *SheetsRepository) GetTimeEntries(date string) ([]models.TimeEntry, erro
 			return []models.TimeEntry{}, nil
 		}
 
-		return nil, fmt.Errorf("データの取得に失敗しました: %v", err)
+		return nil, fmt.Errorf("データの取得に失敗しました: %w", classifyAPIError(err))
 	}
 
 	fmt.Printf("取得したデータの行数: %d\n", len(resp.Values))
 	for i, row := range resp.Values {
-		fmt.Printf("行 %d: 列数=%d, 内容=%v\n", i+2, len(row), row) // A2から始まるのでインデックスは+2
+		fmt.Printf("行 %d: 列数=%d, 内容=%v\n", i+1, len(row), row) // A1から始まるのでインデックスは+1
+	}
+
+	entries := parseTimeEntryRows(resp.Values, r.CustomFields)
+	fmt.Printf("取得したエントリ数: %d\n", len(entries))
+	return entries, nil
+}
+
+// GetTimeEntriesRange は from〜to（両端を含む）の日付シートを1回のBatchGetでまとめて取得します
+func (r *SheetsRepository) GetTimeEntriesRange(ctx context.Context, from, to string) (map[string][]models.TimeEntry, error) {
+	// 対象となる日付シートを特定するため、シート名のみを取得
+	spreadsheet, err := r.Service.Spreadsheets.Get(r.spreadsheetID).Fields("sheets.properties.title").Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("シート一覧の取得に失敗しました: %w", classifyAPIError(err))
+	}
+
+	var dates []string
+	for _, sheet := range spreadsheet.Sheets {
+		date, ok := entrySheetDate(ctx, sheet.Properties.Title)
+		if !ok {
+			continue
+		}
+		// YYYY-MM-DD形式は文字列比較で日付順になる
+		if date >= from && date <= to {
+			dates = append(dates, date)
+		}
+	}
+
+	result := make(map[string][]models.TimeEntry)
+	if len(dates) == 0 {
+		return result, nil
+	}
+
+	ranges := make([]string, len(dates))
+	for i, date := range dates {
+		ranges[i] = r.entryRange(entrySheetTitle(ctx, date))
+	}
+	fmt.Printf("スプレッドシートから期間データを取得します: ID=%s, シート数=%d (%s〜%s)\n", r.spreadsheetID, len(dates), from, to)
+
+	resp, err := r.Service.Spreadsheets.Values.BatchGet(r.spreadsheetID).Ranges(ranges...).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("期間データの取得に失敗しました: %w", classifyAPIError(err))
+	}
+
+	// BatchGetの結果はリクエストした範囲と同じ順序で返される
+	for i, valueRange := range resp.ValueRanges {
+		if i >= len(dates) {
+			break
+		}
+		entries := parseTimeEntryRows(valueRange.Values, r.CustomFields)
+		if len(entries) > 0 {
+			result[dates[i]] = entries
+		}
+	}
+
+	return result, nil
+}
+
+// isDateSheetTitle はシート名が日付シート（YYYY-MM-DD）かどうかを判定します
+func isDateSheetTitle(title string) bool {
+	_, err := time.Parse("2006-01-02", title)
+	return err == nil
+}
+
+// entrySheetTitle は ctx のユーザーの日付シートの名前（ユーザーがない場合は日付のみ）を返します
+func entrySheetTitle(ctx context.Context, date string) string {
+	if user := UserFromContext(ctx); user != "" {
+		return user + "/" + date
+	}
+	return date
+}
+
+// entrySheetDate はシートが ctx のユーザーの日付シートであればその日付を返します
+func entrySheetDate(ctx context.Context, title string) (string, bool) {
+	date := title
+	if user := UserFromContext(ctx); user != "" {
+		var ok bool
+		if date, ok = strings.CutPrefix(title, user+"/"); !ok {
+			return "", false
+		}
+	}
+	return date, isDateSheetTitle(date)
+}
+
+// entryIDColumn はエントリのIDを保存する列（項目の列の次、I列）の位置です
+const entryIDColumn = 8
+
+// entryRange は日付シートのうち見出し行を含めて読み込む範囲を返します（カスタム項目の列まで）
+// シート名に / などを含む場合があるため引用符で囲みます
+func (r *SheetsRepository) entryRange(title string) string {
+	quoted := "'" + strings.ReplaceAll(title, "'", "''") + "'"
+	return quoted + "!A1:" + ColumnName(entryIDColumn+len(r.CustomFields))
+}
+
+// ColumnName は列の位置（0始まり）をスプレッドシートの列名（A, B, ..., Z, AA, ...）に変換します
+func ColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// parseTimeEntryRows はシートの行（1行目は見出し）をタイムエントリに変換します
+// カスタム項目は見出しで列を特定するため、設定の順序を変えても読み込めます
+// ID列が空の行（IDの導入前に保存された行）には行番号からIDを割り当て、次回の保存時に書き込みます
+func parseTimeEntryRows(rows [][]interface{}, customFields []models.CustomField) []models.TimeEntry {
+	if len(rows) == 0 {
+		return nil
+	}
+	customColumns := make(map[int]models.CustomField)
+	for col := entryIDColumn + 1; col < len(rows[0]); col++ {
+		label := getStringValueFromRow(rows[0], col)
+		for _, f := range customFields {
+			if label != "" && f.Matches(label) {
+				customColumns[col] = f
+				break
+			}
+		}
 	}
 
 	var entries []models.TimeEntry
-	for i, row := range resp.Values {
+	for i, row := range rows[1:] {
 		// 行が少なくとも1つの要素（時間）を持っていることを確認
 		if len(row) < 1 {
 			fmt.Printf("スキップ: 行 %d は要素がありません\n", i+2)
@@ -89,42 +235,60 @@ func (r *SheetsRepository) GetTimeEntries(date string) ([]models.TimeEntry, erro
 		}
 
 		// 各フィールドを文字列として取得（存在しない場合は空文字）
-		timeStr := getStringValueFromRow(row, 0)
-		if timeStr == "" {
+		var entry models.TimeEntry
+		for _, field := range models.Fields {
+			if field.Key == models.FieldTime {
+				field.SetValue(&entry, getTimeValueFromRow(row, field.Column))
+			} else {
+				field.SetValue(&entry, getStringValueFromRow(row, field.Column))
+			}
+		}
+		if entry.Time == "" {
 			fmt.Printf("スキップ: 行 %d は時間がありません\n", i+2)
 			continue // 時間が空の行はスキップ（必須項目）
 		}
-
-		contentStr := getStringValueFromRow(row, 1)
-		if contentStr == "" {
+		if entry.Content == "" {
 			fmt.Printf("スキップ: 行 %d は内容がありません\n", i+2)
 			continue // 内容が空の行はスキップ（必須項目）
 		}
-
 		// その他のフィールドは空でも許容
-		clientStr := getStringValueFromRow(row, 2)
-		purposeStr := getStringValueFromRow(row, 3)
-		actionStr := getStringValueFromRow(row, 4)
-		withStr := getStringValueFromRow(row, 5)
-		pcccStr := getStringValueFromRow(row, 6)
-		remarkStr := getStringValueFromRow(row, 7)
-
-		entry := models.TimeEntry{
-			Time:    timeStr,
-			Content: contentStr,
-			Client:  clientStr,
-			Purpose: purposeStr,
-			Action:  actionStr,
-			With:    withStr,
-			PcCc:    pcccStr,
-			Remark:  remarkStr,
+
+		entry.ID = getStringValueFromRow(row, entryIDColumn)
+		if entry.ID == "" {
+			entry.ID = fmt.Sprintf("row-%d", i+2)
 		}
+
+		for col, f := range customColumns {
+			value, err := f.Parse(getStringValueFromRow(row, col))
+			if err != nil {
+				fmt.Printf("行 %d の %s を読み込めません: %v\n", i+2, f.DisplayLabel(), err)
+				continue
+			}
+			if value != nil {
+				if entry.Custom == nil {
+					entry.Custom = make(map[string]interface{})
+				}
+				entry.Custom[f.Key] = value
+			}
+		}
+
 		entries = append(entries, entry)
-		fmt.Printf("追加: 行 %d - 時間=%s, 内容=%s, 備考=%s\n", i+2, timeStr, contentStr, remarkStr)
+		fmt.Printf("追加: 行 %d - 時間=%s, 内容=%s, 備考=%s\n", i+2, entry.Time, entry.Content, entry.Remark)
 	}
 
-	fmt.Printf("取得したエントリ数: %d\n", len(entries))
-	return entries, nil
+	return entries
+}
+
+// getTimeValueFromRow は時間のセルを取得し、数値（スプレッドシートの時間値など）の場合は正規化します
+func getTimeValueFromRow(row []interface{}, index int) string {
+	if index < len(row) {
+		if _, ok := row[index].(float64); ok {
+			if slot, err := models.ParseTimeSlotValue(row[index]); err == nil {
+				return slot.String()
+			}
+		}
+	}
+	return getStringValueFromRow(row, index)
 }
 
 // 行から安全に文字列値を取得するヘルパー関数
@@ -141,91 +305,50 @@ func getStringValueFromRow(row []interface{}, index int) string {
 	return "" // インデックスが範囲外または値がnilの場合
 }
 
-func (r *SheetsRepository) SaveTimeEntries(date string, entries []models.TimeEntry) (time.Time, error) {
-	// シートが存在しない場合は作成
-	sheetExists := false
-	sheets, err := r.Service.Spreadsheets.Get(r.spreadsheetID).Do()
-	if err != nil {
-		return time.Time{}, fmt.Errorf("シート一覧の取得に失敗しました: %v", err)
-	}
-
-	for _, sheet := range sheets.Sheets {
-		if sheet.Properties.Title == date {
-			sheetExists = true
-			break
-		}
+// SaveTimeEntries は ctx のユーザーの日付シートの内容を置き換えます
+// 書き込みと余分な行の削除を1回の BatchUpdate で行うため、途中で失敗しても以前の内容は失われません
+func (r *SheetsRepository) SaveTimeEntries(ctx context.Context, date string, entries []models.TimeEntry) (time.Time, error) {
+	// ヘッダー行を準備（項目の見出し、ID、カスタム項目の見出し）
+	width := entryIDColumn + 1 + len(r.CustomFields)
+	header := make([]interface{}, width)
+	for _, field := range models.Fields {
+		header[field.Column] = field.Label
 	}
-
-	if !sheetExists {
-		// 新しいシートを作成
-		requests := []*sheetsv4.Request{
-			{
-				AddSheet: &sheetsv4.AddSheetRequest{
-					Properties: &sheetsv4.SheetProperties{
-						Title: date,
-					},
-				},
-			},
-		}
-
-		_, err := r.Service.Spreadsheets.BatchUpdate(r.spreadsheetID, &sheetsv4.BatchUpdateSpreadsheetRequest{
-			Requests: requests,
-		}).Do()
-		if err != nil {
-			return time.Time{}, fmt.Errorf("シートの作成に失敗しました: %v", err)
-		}
-	}
-
-	// ヘッダー行を準備
-	header := [][]interface{}{
-		{"時間", "内容", "クライアント", "目的", "アクション", "誰と", "PC/CC", "備考"},
+	header[entryIDColumn] = "ID"
+	for i, f := range r.CustomFields {
+		header[entryIDColumn+1+i] = f.DisplayLabel()
 	}
+	values := [][]interface{}{header}
 
 	// データ行を準備
-	var values [][]interface{}
-	for _, entry := range entries {
-		row := []interface{}{
-			entry.Time,
-			entry.Content,
-			entry.Client,
-			entry.Purpose,
-			entry.Action,
-			entry.With,
-			entry.PcCc,
-			entry.Remark,
+	for _, entry := range models.EnsureEntryIDs(entries) {
+		row := make([]interface{}, width)
+		for _, field := range models.Fields {
+			row[field.Column] = field.Value(entry)
+		}
+		row[models.Fields[0].Column] = models.NormalizeTime(entry.Time)
+		row[entryIDColumn] = entry.ID
+		for i, f := range r.CustomFields {
+			// 数値や真偽値はそのままの型でセルに書き込む
+			row[entryIDColumn+1+i] = entry.Custom[f.Key]
 		}
 		values = append(values, row)
 	}
 
-	// データを書き込む
-	valueRange := &sheetsv4.ValueRange{
-		Values: append(header, values...),
-	}
-
-	// 既存のデータをクリア
-	_, err = r.Service.Spreadsheets.Values.Clear(r.spreadsheetID, date+"!A1:Z", &sheetsv4.ClearValuesRequest{}).Do()
-	if err != nil {
-		return time.Time{}, fmt.Errorf("データのクリアに失敗しました: %v", err)
-	}
-
-	// 新しいデータを書き込む
-	_, err = r.Service.Spreadsheets.Values.Update(r.spreadsheetID, date+"!A1", valueRange).
-		ValueInputOption("RAW").
-		Do()
-	if err != nil {
-		return time.Time{}, fmt.Errorf("データの書き込みに失敗しました: %v", err)
+	if err := r.replaceSheetValues(ctx, entrySheetTitle(ctx, date), values); err != nil {
+		return time.Time{}, err
 	}
 
 	return time.Now(), nil
 }
 
-func (r *SheetsRepository) GetDbItems() ([]models.DbItem, error) {
-	// スプレッドシートからデータを取得 (A列からH列まで読み取る)
-	rangeStr := "業務データベース!A:H"
+func (r *SheetsRepository) GetDbItems(ctx context.Context) ([]models.DbItem, error) {
+	// スプレッドシートからデータを取得 (A列からZ列まで読み取る)
+	rangeStr := r.DbItemsSheet + dbItemsColumns
 	fmt.Printf("スプレッドシートからデータを取得します: ID=%s, Range=%s\n", r.spreadsheetID, rangeStr)
 
 	// まずスプレッドシートのすべてのシート名を取得して確認
-	sheets, err := r.Service.Spreadsheets.Get(r.spreadsheetID).Do()
+	sheets, err := r.Service.Spreadsheets.Get(r.spreadsheetID).Context(ctx).Do()
 	if err != nil {
 		fmt.Printf("スプレッドシート情報の取得に失敗しました: %v\n", err)
 	} else {
@@ -235,7 +358,7 @@ func (r *SheetsRepository) GetDbItems() ([]models.DbItem, error) {
 		}
 	}
 
-	resp, err := r.Service.Spreadsheets.Values.Get(r.spreadsheetID, rangeStr).Do()
+	resp, err := r.Service.Spreadsheets.Values.Get(r.spreadsheetID, rangeStr).Context(ctx).Do()
 	if err != nil {
 		// Check if the error is due to the sheet not existing or being empty
 		if strings.Contains(err.Error(), "Unable to parse range") || strings.Contains(err.Error(), "404") {
@@ -243,7 +366,7 @@ func (r *SheetsRepository) GetDbItems() ([]models.DbItem, error) {
 			return []models.DbItem{}, nil // Return empty list if sheet is not found or empty
 		}
 		fmt.Printf("詳細なエラー: %v\n", err)
-		return nil, fmt.Errorf("スプレッドシートからデータを取得できませんでした: %v", err)
+		return nil, fmt.Errorf("スプレッドシートからデータを取得できませんでした: %w", classifyAPIError(err))
 	}
 
 	fmt.Printf("取得したデータ: %+v\n", resp.Values)
@@ -256,17 +379,26 @@ func (r *SheetsRepository) GetDbItems() ([]models.DbItem, error) {
 	}
 	fmt.Println("==============================")
 
-	if len(resp.Values) == 0 {
+	items := parseDbItemsRows(resp.Values)
+	fmt.Printf("変換後のアイテム数: %d\n", len(items))
+	return items, nil
+}
+
+// parseDbItemsRows は業務データベースのシートの行を項目に変換します
+// 1行目が種別ごとの見出しの形式と、「項目種別 / 項目名」形式（A列が種別、B列が値）の両方に対応します
+func parseDbItemsRows(rows [][]interface{}) []models.DbItem {
+	if len(rows) == 0 {
 		fmt.Println("シートにデータがありません。")
-		return []models.DbItem{}, nil // No data in the sheet
+		return []models.DbItem{} // No data in the sheet
 	}
 
 	// データを変換
 	var items []models.DbItem
-	headerRow := resp.Values[0] // 最初の行をヘッダーとして取得
+	headerRow := rows[0] // 最初の行をヘッダーとして取得
 	idCounter := int64(1)
 
 	// 列ごとに処理
+columns:
 	for colIndex, headerCell := range headerRow {
 		headerStr, ok := headerCell.(string)
 		if !ok || headerStr == "" {
@@ -274,43 +406,19 @@ func (r *SheetsRepository) GetDbItems() ([]models.DbItem, error) {
 			continue // Skip columns with invalid headers
 		}
 
-		// ヘッダー名をTypeとして使用 (小文字に統一)
-		itemType := strings.ToLower(headerStr)
-		// 新しいフィールド名にマッピング
-		switch itemType {
-		case "内容":
-			itemType = "content"
-		case "クライアント":
-			itemType = "client"
-		case "目的":
-			itemType = "purpose"
-		case "アクション":
-			itemType = "action"
-		case "誰と":
-			itemType = "with"
-		case "pc/cc":
-			itemType = "pccc"
-		case "備考":
-			itemType = "remark"
-		// 古いフィールド名もサポート
-		case "task":
-			itemType = "content"
-		case "function":
-			itemType = "action"
-		case "mall":
-			itemType = "with"
-		case "costtype":
-			itemType = "pccc"
+		// ヘッダー名をTypeとして使用 (小文字に統一し、新しいフィールド名にマッピング)
+		itemType := models.NormalizeFieldKey(headerStr)
+		switch {
 		// 時間はスキップ
-		case "時間":
+		case itemType == models.FieldTime:
 			continue
-		case "項目種別", "項目名":
+		case headerStr == dbItemsTypeHeader || headerStr == dbItemsValueHeader:
 			// A1:B形式の場合、特別な処理
 			if colIndex == 0 && len(headerRow) >= 2 {
-				if secondHeader, ok := headerRow[1].(string); ok && secondHeader == "項目名" {
+				if secondHeader, ok := headerRow[1].(string); ok && secondHeader == dbItemsValueHeader {
 					// A列がType、B列がValueの形式と判断
-					for rowIndex := 1; rowIndex < len(resp.Values); rowIndex++ {
-						row := resp.Values[rowIndex]
+					for rowIndex := 1; rowIndex < len(rows); rowIndex++ {
+						row := rows[rowIndex]
 						if len(row) < 2 {
 							continue // Skip incomplete rows
 						}
@@ -320,23 +428,7 @@ func (r *SheetsRepository) GetDbItems() ([]models.DbItem, error) {
 
 						if typeVal != "" && valueVal != "" {
 							// typeを正規化
-							normalizedType := strings.ToLower(typeVal)
-							switch normalizedType {
-							case "task", "内容":
-								normalizedType = "content"
-							case "function", "機能別", "アクション":
-								normalizedType = "action"
-							case "mall", "モール別", "誰と":
-								normalizedType = "with"
-							case "costtype", "コスト区分", "pc/cc":
-								normalizedType = "pccc"
-							case "備考":
-								normalizedType = "remark"
-							case "client", "クライアント":
-								normalizedType = "client"
-							case "purpose", "目的":
-								normalizedType = "purpose"
-							}
+							normalizedType := models.NormalizeFieldKey(typeVal)
 
 							items = append(items, models.DbItem{
 								ID:    idCounter,
@@ -347,15 +439,15 @@ func (r *SheetsRepository) GetDbItems() ([]models.DbItem, error) {
 						}
 					}
 					// A:B形式の処理が終わったのでループを抜ける
-					break
+					break columns
 				}
 			}
 			continue // 通常の列ベースの処理には含めない
 		}
 
 		// 2行目以降のデータをValueとして取得
-		for rowIndex := 1; rowIndex < len(resp.Values); rowIndex++ {
-			row := resp.Values[rowIndex]
+		for rowIndex := 1; rowIndex < len(rows); rowIndex++ {
+			row := rows[rowIndex]
 			// 列インデックスが現在の行の範囲内にあるか確認
 			if colIndex < len(row) {
 				valueStr := getStringValueFromRow(row, colIndex)
@@ -372,142 +464,28 @@ func (r *SheetsRepository) GetDbItems() ([]models.DbItem, error) {
 		}
 	}
 
-	fmt.Printf("変換後のアイテム数: %d\n", len(items))
-	return items, nil
+	return items
 }
 
-func (r *SheetsRepository) SaveDbItems(items []models.DbItem) error {
-	// 注意: このメソッドは現在の GetDbItems の構造と整合性が取れていない可能性があります。
-	//       GetDbItems が列ベースでデータを読むようになったため、
-	//       保存ロジックもそれに対応するか、別途検討が必要です。
-	//       一旦、既存のロジックを残しますが、意図通りに動作しない可能性があります。
-
-	// データを書き込む前に既存のデータを取得 (修正後のGetDbItemsを呼ぶ)
-	existingItems, err := r.GetDbItems()
+// SaveDbItems は業務データベースを items の内容に置き換えます
+// シートの形式（種別ごとの列、または「項目種別 / 項目名」）と見出しを保ったまま、items の順序で書き込みます
+func (r *SheetsRepository) SaveDbItems(ctx context.Context, items []models.DbItem) error {
+	rows, err := r.readDbItemsRows(ctx)
 	if err != nil {
-		// GetDbItemsが空を返す場合のエラーハンドリングを追加
-		if err.Error() == "シートにデータがありません。" {
-			existingItems = []models.DbItem{} // Treat as empty
-		} else {
-			return fmt.Errorf("既存のデータの取得に失敗しました: %v", err)
-		}
-	}
-
-	// 既存のデータと新しいデータをマージ (TypeとValueのペアで重複排除)
-	mergedItemsMap := make(map[string]map[string]bool)
-
-	// 既存のデータを追加
-	for _, item := range existingItems {
-		if _, ok := mergedItemsMap[item.Type]; !ok {
-			mergedItemsMap[item.Type] = make(map[string]bool)
-		}
-		mergedItemsMap[item.Type][item.Value] = true
-	}
-
-	// 新しいデータを追加（重複を避ける）
-	for _, item := range items {
-		// Typeを小文字に変換して統一
-		normalizedType := strings.ToLower(item.Type)
-		// マッピングを適用 (GetDbItemsと同様)
-		switch normalizedType {
-		case "task", "内容":
-			normalizedType = "content"
-		case "function", "機能別", "アクション":
-			normalizedType = "action"
-		case "mall", "モール別", "誰と":
-			normalizedType = "with"
-		case "costtype", "コスト区分", "pc/cc":
-			normalizedType = "pccc"
-		case "remark", "備考":
-			normalizedType = "remark"
-		case "client", "クライアント":
-			normalizedType = "client"
-		case "purpose", "目的":
-			normalizedType = "purpose"
-		default:
-			// 知らないTypeはスキップするか、エラーにするか検討
-			fmt.Printf("不明なDB項目タイプです: %s (値: %s)\n", item.Type, item.Value)
-			continue
-		}
-
-		if _, ok := mergedItemsMap[normalizedType]; !ok {
-			mergedItemsMap[normalizedType] = make(map[string]bool)
-		}
-		mergedItemsMap[normalizedType][item.Value] = true
-	}
-
-	// --- 保存形式の再考が必要 ---
-	// 現在のSaveDbItemsは、TypeとValueのペアをA列とB列に書き込む想定。
-	// しかし、GetDbItemsは列ヘッダーをTypeとして読むようになった。
-	// このままでは、SaveDbItemsは意図した形式で「業務データベース」シートに書き込めない。
-	//
-	// 例：GetDbItemsで Content列から ["会議", "資料作成"] を読み込んだ場合、
-	//    SaveDbItemsで書き込むデータは [][]interface{}{{"content", "会議"}, {"content", "資料作成"}} となり、
-	//    これをA列B列に書き込んでしまう。
-	//
-	// **対応策案:**
-	// 1. SaveDbItems を無効化/削除する (DB項目は手動でメンテすると割り切る)
-	// 2. SaveDbItems を大幅に修正し、列ベースの書き込みに対応する
-	//    - マージ後の `mergedItemsMap` から、列ヘッダーと値のリストを作成する。
-	//    - 例: {"content": {"会議": true, "資料作成": true}, "client": {"A社": true}}
-	//      -> ヘッダー: ["content", "client"]
-	//      -> データ:   [["会議", "A社"], ["資料作成", ""]]
-	//    - これをスプレッドシートに書き込む。実装が複雑になる。
-	//
-	// ここでは一旦、元のA:B書き込みロジックを残すが、コメントで注意喚起する。
-
-	// マージしたデータをスライスに変換 (元のA:B書き込み形式)
-	var values [][]interface{}
-	fmt.Println("--- SaveDbItems: 注意 ---")
-	fmt.Println("現在の保存ロジックはA列B列形式です。GetDbItemsの変更と整合性が取れていない可能性があります。")
-	fmt.Println("業務データベースシートの更新は手動で行うか、SaveDbItemsの修正が必要です。")
-	typeToSort := []string{}
-	for itemType := range mergedItemsMap {
-		typeToSort = append(typeToSort, itemType)
-	}
-	// ソートして書き込み順序を安定させる（任意）
-	// sort.Strings(typeToSort)
-
-	for _, itemType := range typeToSort {
-		for value := range mergedItemsMap[itemType] {
-			// 元のヘッダー名に戻す方が親切かもしれないが、一旦小文字のまま
-			values = append(values, []interface{}{itemType, value})
-		}
-	}
-
-	// データを書き込む
-	valueRange := &sheetsv4.ValueRange{
-		Values: values,
+		return err
 	}
 
-	// 既存のデータをクリア (A:Bのみクリア)
-	clearRange := "業務データベース!A:B"
-	_, err = r.Service.Spreadsheets.Values.Clear(r.spreadsheetID, clearRange, &sheetsv4.ClearValuesRequest{}).Do()
-	if err != nil {
-		// クリア対象が存在しなくてもエラーになることがあるため、特定のエラーは無視する可能性がある
-		fmt.Printf("DBアイテムのクリアに失敗しました (無視される可能性あり): %v\n", err)
-		// return fmt.Errorf("データのクリアに失敗しました: %v", err)
-	}
-
-	if len(values) > 0 {
-		// 新しいデータを書き込む (A1から書き込み)
-		updateRange := "業務データベース!A1"
-		_, err = r.Service.Spreadsheets.Values.Update(r.spreadsheetID, updateRange, valueRange).
-			ValueInputOption("RAW").
-			Do()
-		if err != nil {
-			return fmt.Errorf("データの書き込みに失敗しました: %v", err)
-		}
-	} else {
-		fmt.Println("書き込むDBアイテムがありません。クリアのみ実行されました。")
+	values := buildDbItemsValues(rows, items)
+	if err := r.replaceSheetValues(ctx, r.DbItemsSheet, values); err != nil {
+		return fmt.Errorf("業務データベースの書き込みに失敗しました: %w", err)
 	}
-
 	return nil
 }
 
-func (r *SheetsRepository) DeleteDbItems(items []models.DbItem) error {
+// DeleteDbItems は業務データベースから items と同じ種別・値の項目を削除します
+func (r *SheetsRepository) DeleteDbItems(ctx context.Context, items []models.DbItem) error {
 	// 現在のデータを取得
-	currentItems, err := r.GetDbItems()
+	currentItems, err := r.GetDbItems(ctx)
 	if err != nil {
 		return err
 	}
@@ -517,7 +495,7 @@ func (r *SheetsRepository) DeleteDbItems(items []models.DbItem) error {
 	for _, currentItem := range currentItems {
 		shouldDelete := false
 		for _, itemToDelete := range items {
-			if currentItem.Type == itemToDelete.Type && currentItem.Value == itemToDelete.Value {
+			if currentItem.Type == models.NormalizeFieldKey(itemToDelete.Type) && currentItem.Value == itemToDelete.Value {
 				shouldDelete = true
 				break
 			}
@@ -527,19 +505,6 @@ func (r *SheetsRepository) DeleteDbItems(items []models.DbItem) error {
 		}
 	}
 
-	// 残りのアイテムを保存
-	if len(remainingItems) == 0 {
-		// すべてのアイテムが削除された場合は、空のデータを保存
-		valueRange := &sheetsv4.ValueRange{
-			Values: [][]interface{}{
-				{"項目種別", "項目名"},
-			},
-		}
-		_, err = r.Service.Spreadsheets.Values.Update(r.spreadsheetID, "業務データベース!A1", valueRange).
-			ValueInputOption("RAW").
-			Do()
-		return err
-	}
-
-	return r.SaveDbItems(remainingItems)
+	// 残りのアイテムを保存（すべて削除された場合も見出しは残る）
+	return r.SaveDbItems(ctx, remainingItems)
 }
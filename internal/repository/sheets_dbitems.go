@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// dbItemsColumns は業務データベースのシートを読み書きする列の範囲です
+const dbItemsColumns = "!A:Z"
+
+// 業務データベースの「項目種別 / 項目名」形式の見出し
+const (
+	dbItemsTypeHeader  = "項目種別"
+	dbItemsValueHeader = "項目名"
+)
+
+// dbItemLabel は種別の既定の見出しを返します
+func dbItemLabel(itemType string) string {
+	if f, ok := models.LookupField(itemType); ok {
+		return f.Label
+	}
+	return itemType
+}
+
+// isDbItemsPairLayout は見出しが「項目種別 / 項目名」形式（A列が種別、B列が値）かどうかを判定します
+func isDbItemsPairLayout(header []interface{}) bool {
+	return len(header) >= 2 &&
+		getStringValueFromRow(header, 0) == dbItemsTypeHeader &&
+		getStringValueFromRow(header, 1) == dbItemsValueHeader
+}
+
+// dbItemsColumnType は列ごとの形式の見出しから種別を返します
+// GetDbItems が読み込まない列（見出しが空、時間など）の場合は ok=false を返します
+func dbItemsColumnType(header []interface{}, index int) (itemType string, ok bool) {
+	label, isString := header[index].(string)
+	if !isString || label == "" || label == dbItemsTypeHeader || label == dbItemsValueHeader {
+		return "", false
+	}
+	itemType = models.NormalizeFieldKey(label)
+	if itemType == models.FieldTime {
+		return "", false
+	}
+	return itemType, true
+}
+
+// groupDbItems は項目を種別ごとにまとめます。種別・値の順序は最初に現れた順を保ち、重複と空の値は除きます
+func groupDbItems(items []models.DbItem) (types []string, values map[string][]string) {
+	values = make(map[string][]string)
+	seen := make(map[string]map[string]bool)
+	for _, item := range items {
+		if item.Value == "" {
+			continue
+		}
+		itemType := models.NormalizeFieldKey(item.Type)
+		if seen[itemType] == nil {
+			seen[itemType] = make(map[string]bool)
+			types = append(types, itemType)
+		}
+		if seen[itemType][item.Value] {
+			continue
+		}
+		seen[itemType][item.Value] = true
+		values[itemType] = append(values[itemType], item.Value)
+	}
+	return types, values
+}
+
+// buildDbItemsValues は現在のシートの内容 rows の形式に合わせて、items を書き込むシートの内容を作成します
+// 見出しは元のまま残し、GetDbItems が読み込まない列は元の値をそのまま書き戻します
+func buildDbItemsValues(rows [][]interface{}, items []models.DbItem) [][]interface{} {
+	var header []interface{}
+	if len(rows) > 0 {
+		header = rows[0]
+	}
+	if isDbItemsPairLayout(header) {
+		return buildDbItemsPairValues(rows, items)
+	}
+	return buildDbItemsColumnValues(rows, items)
+}
+
+// buildDbItemsColumnValues は種別ごとに列を分けた形式で書き込む内容を作成します
+func buildDbItemsColumnValues(rows [][]interface{}, items []models.DbItem) [][]interface{} {
+	types, values := groupDbItems(items)
+
+	var header []interface{}
+	if len(rows) > 0 {
+		header = append(header, rows[0]...)
+	} else {
+		// 空のシートには既定の列をすべて作成する
+		for _, f := range models.DbItemFields() {
+			header = append(header, f.Label)
+		}
+	}
+
+	// 見出しのない種別は末尾に列を追加する
+	present := make(map[string]bool)
+	for i := range header {
+		if itemType, ok := dbItemsColumnType(header, i); ok {
+			present[itemType] = true
+		}
+	}
+	for _, itemType := range types {
+		if !present[itemType] {
+			header = append(header, dbItemLabel(itemType))
+			present[itemType] = true
+		}
+	}
+
+	columns := make([][]interface{}, len(header))
+	written := make(map[string]bool)
+	height := 0
+	for i := range header {
+		if itemType, ok := dbItemsColumnType(header, i); ok {
+			// 同じ種別の列が複数ある場合は最初の列にまとめる
+			if !written[itemType] {
+				written[itemType] = true
+				for _, value := range values[itemType] {
+					columns[i] = append(columns[i], value)
+				}
+			}
+		} else if len(rows) > 0 {
+			for _, row := range rows[1:] {
+				var cell interface{}
+				if i < len(row) {
+					cell = row[i]
+				}
+				columns[i] = append(columns[i], cell)
+			}
+		}
+		height = max(height, len(columns[i]))
+	}
+
+	result := [][]interface{}{header}
+	for r := 0; r < height; r++ {
+		row := make([]interface{}, len(header))
+		for i, column := range columns {
+			if r < len(column) {
+				row[i] = column[r]
+			} else {
+				row[i] = ""
+			}
+		}
+		result = append(result, row)
+	}
+	return result
+}
+
+// buildDbItemsPairValues は「項目種別 / 項目名」形式で書き込む内容を作成します
+// 種別は元のシートで使われていた名前（機能別など）で書き戻します
+func buildDbItemsPairValues(rows [][]interface{}, items []models.DbItem) [][]interface{} {
+	labels := make(map[string]string)
+	for _, row := range rows[1:] {
+		label := getStringValueFromRow(row, 0)
+		if label == "" {
+			continue
+		}
+		itemType := models.NormalizeFieldKey(label)
+		if _, ok := labels[itemType]; !ok {
+			labels[itemType] = label
+		}
+	}
+
+	// 項目は items の順序のまま書き込む（種別ごとにまとめ直さない）
+	var pairs [][]interface{}
+	seen := make(map[models.DbItem]bool)
+	for _, item := range items {
+		itemType := models.NormalizeFieldKey(item.Type)
+		key := models.DbItem{Type: itemType, Value: item.Value}
+		if item.Value == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		label, ok := labels[itemType]
+		if !ok {
+			label = dbItemLabel(itemType)
+		}
+		pairs = append(pairs, []interface{}{label, item.Value})
+	}
+
+	// C列以降は GetDbItems が読み込まないため、元の値をそのまま残す
+	height := max(len(pairs), len(rows)-1)
+	result := [][]interface{}{rows[0]}
+	for r := 0; r < height; r++ {
+		row := []interface{}{"", ""}
+		if r < len(pairs) {
+			row = append([]interface{}{}, pairs[r]...)
+		}
+		if r+1 < len(rows) && len(rows[r+1]) > 2 {
+			row = append(row, rows[r+1][2:]...)
+		}
+		result = append(result, row)
+	}
+	return result
+}
+
+// readDbItemsRows は業務データベースのシートの内容を取得します。シートが存在しない場合は空を返します
+func (r *SheetsRepository) readDbItemsRows(ctx context.Context) ([][]interface{}, error) {
+	resp, err := r.Service.Spreadsheets.Values.Get(r.spreadsheetID, r.DbItemsSheet+dbItemsColumns).Context(ctx).Do()
+	if err != nil {
+		if strings.Contains(err.Error(), "Unable to parse range") || strings.Contains(err.Error(), "404") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("業務データベースの取得に失敗しました: %w", classifyAPIError(err))
+	}
+	return resp.Values, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+func TestDbItemsRoundTrip(t *testing.T) {
+	type cell struct {
+		row, col int
+		value    interface{}
+	}
+	tests := []struct {
+		name  string
+		rows  [][]interface{} // 保存前のシートの内容
+		items []models.DbItem // 保存する項目（種別はキー）
+		keep  []cell          // 保存後も残っているはずのセル
+	}{
+		{
+			name: "種別ごとの列",
+			rows: [][]interface{}{
+				{"内容", "クライアント", "目的"},
+				{"旧内容", "旧クライアント", "旧目的"},
+				{"旧内容2"},
+			},
+			items: []models.DbItem{
+				{Type: models.FieldContent, Value: "設計"},
+				{Type: models.FieldContent, Value: "会議"},
+				{Type: models.FieldClient, Value: "A社"},
+				{Type: models.FieldPurpose, Value: "開発"},
+				{Type: models.FieldWith, Value: "田中"}, // 見出しのない種別は列を追加する
+			},
+			keep: []cell{{0, 0, "内容"}, {0, 3, "誰と"}},
+		},
+		{
+			name: "項目種別/項目名",
+			rows: [][]interface{}{
+				{"項目種別", "項目名"},
+				{"機能別", "旧アクション"},
+				{"クライアント", "旧クライアント"},
+			},
+			items: []models.DbItem{
+				{Type: models.FieldAction, Value: "企画"},
+				{Type: models.FieldClient, Value: "A社"},
+				{Type: models.FieldContent, Value: "設計"},
+			},
+			// 種別は元のシートの名前（機能別）で書き戻す
+			keep: []cell{{1, 0, "機能別"}, {3, 0, "内容"}},
+		},
+		{
+			name: "読み込まない列（時間・見出しのない列）",
+			rows: [][]interface{}{
+				{"時間", "内容", "", "クライアント"},
+				{"09:00", "旧内容", "メモ1", "旧クライアント"},
+				{"10:00", "", "メモ2"},
+			},
+			items: []models.DbItem{
+				{Type: models.FieldContent, Value: "設計"},
+				{Type: models.FieldClient, Value: "A社"},
+				{Type: models.FieldClient, Value: "B社"},
+			},
+			keep: []cell{{1, 0, "09:00"}, {2, 0, "10:00"}, {1, 2, "メモ1"}, {2, 2, "メモ2"}},
+		},
+		{
+			name: "項目種別/項目名のC列以降",
+			rows: [][]interface{}{
+				{"項目種別", "項目名", "メモ", "更新日"},
+				{"内容", "旧内容", "社内用", "2026-01-01"},
+				{"内容", "旧内容2", "", "2026-01-02"},
+				{"クライアント", "旧クライアント", "要確認"},
+			},
+			items: []models.DbItem{
+				{Type: models.FieldContent, Value: "設計"},
+			},
+			keep: []cell{
+				{0, 2, "メモ"}, {1, 2, "社内用"}, {1, 3, "2026-01-01"},
+				{2, 3, "2026-01-02"}, {3, 2, "要確認"},
+			},
+		},
+		{
+			name: "空のシート",
+			rows: nil,
+			items: []models.DbItem{
+				{Type: models.FieldContent, Value: "設計"},
+				{Type: models.FieldPcCc, Value: "PC"},
+				{Type: models.FieldRemark, Value: "備考1"},
+			},
+			keep: []cell{{0, 0, "内容"}, {0, 6, "備考"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rebuilt := buildDbItemsValues(tt.rows, tt.items)
+
+			got := stripDbItemIDs(parseDbItemsRows(rebuilt))
+			if !reflect.DeepEqual(got, tt.items) {
+				t.Errorf("読み込み結果 = %v, want %v\nrows=%v", got, tt.items, rebuilt)
+			}
+
+			for _, c := range tt.keep {
+				if c.row >= len(rebuilt) || c.col >= len(rebuilt[c.row]) || rebuilt[c.row][c.col] != c.value {
+					t.Errorf("セル(%d, %d) が %v ではありません\nrows=%v", c.row, c.col, c.value, rebuilt)
+				}
+			}
+
+			// 保存した内容を再度保存しても変わらない
+			if again := buildDbItemsValues(rebuilt, tt.items); !reflect.DeepEqual(again, rebuilt) {
+				t.Errorf("再保存で内容が変わりました:\nfirst=%v\nagain=%v", rebuilt, again)
+			}
+		})
+	}
+}
+
+func TestDbItemsRoundTripNormalizesLabels(t *testing.T) {
+	// 種別を見出し（日本語）で指定した場合もキーとして読み込める
+	items := []models.DbItem{
+		{Type: "クライアント", Value: "A社"},
+		{Type: "機能別", Value: "企画"},
+	}
+	got := stripDbItemIDs(parseDbItemsRows(buildDbItemsValues(nil, items)))
+	want := []models.DbItem{
+		{Type: models.FieldClient, Value: "A社"},
+		{Type: models.FieldAction, Value: "企画"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("読み込み結果 = %v, want %v", got, want)
+	}
+}
+
+func stripDbItemIDs(items []models.DbItem) []models.DbItem {
+	stripped := make([]models.DbItem, len(items))
+	for i, item := range items {
+		stripped[i] = models.DbItem{Type: item.Type, Value: item.Value}
+	}
+	return stripped
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// DefaultPresetsSheet はプリセットの既定のシート名です
+const DefaultPresetsSheet = "プリセット"
+
+// プリセットのシートの項目以外の列の見出し
+const (
+	presetIDHeader     = "ID"
+	presetNameHeader   = "名前"
+	presetSharedHeader = "共有"
+	presetOwnerHeader  = "所有者"
+	presetRepeatHeader = "曜日"     // 繰り返しの曜日（カンマ区切り）
+	presetCustomHeader = "カスタム項目" // カスタム項目の値（JSON）
+)
+
+// presetHeaders はプリセットのシートの見出しを列の順に返します
+func presetHeaders() []interface{} {
+	header := []interface{}{presetIDHeader, presetNameHeader}
+	for _, f := range models.Fields {
+		header = append(header, f.Label)
+	}
+	return append(header, presetSharedHeader, presetOwnerHeader, presetCustomHeader, presetRepeatHeader)
+}
+
+// GetPresets はプリセットのシートの内容を返します。シートが存在しない場合は空を返します
+// 列は見出しで特定するため、列を並べ替えたシートも読み込めます
+func (r *SheetsRepository) GetPresets(ctx context.Context) ([]models.Preset, error) {
+	resp, err := r.Service.Spreadsheets.Values.Get(r.spreadsheetID, r.PresetsSheet+"!A:Z").Context(ctx).Do()
+	if err != nil {
+		if strings.Contains(err.Error(), "Unable to parse range") || strings.Contains(err.Error(), "404") {
+			return []models.Preset{}, nil
+		}
+		return nil, fmt.Errorf("プリセットの取得に失敗しました: %w", classifyAPIError(err))
+	}
+	return parsePresetRows(resp.Values), nil
+}
+
+// parsePresetRows はシートの行（1行目は見出し）をプリセットに変換します。ID のない行は読み飛ばします
+func parsePresetRows(rows [][]interface{}) []models.Preset {
+	presets := []models.Preset{}
+	if len(rows) == 0 {
+		return presets
+	}
+
+	columns := make(map[string]int)
+	for i := range rows[0] {
+		label := strings.TrimSpace(getStringValueFromRow(rows[0], i))
+		if f, ok := models.LookupField(label); ok {
+			label = f.Key
+		}
+		if _, ok := columns[label]; !ok && label != "" {
+			columns[label] = i
+		}
+	}
+	cell := func(row []interface{}, name string) string {
+		i, ok := columns[name]
+		if !ok {
+			return ""
+		}
+		return getStringValueFromRow(row, i)
+	}
+
+	for i, row := range rows[1:] {
+		var p models.Preset
+		p.ID = cell(row, presetIDHeader)
+		if p.ID == "" {
+			continue
+		}
+		p.Name = cell(row, presetNameHeader)
+		for _, f := range models.Fields {
+			if col, ok := columns[f.Key]; ok {
+				f.SetValue(&p.TimeEntry, getStringValueFromRow(row, col))
+			}
+		}
+		if col, ok := columns[models.FieldTime]; ok {
+			p.Time = getTimeValueFromRow(row, col)
+		}
+		p.Shared, _ = strconv.ParseBool(cell(row, presetSharedHeader))
+		p.Owner = cell(row, presetOwnerHeader)
+		p.Weekdays = splitWeekdays(cell(row, presetRepeatHeader))
+
+		custom, err := decodeCustom(cell(row, presetCustomHeader))
+		if err != nil {
+			fmt.Printf("プリセット 行 %d: %v\n", i+2, err)
+		}
+		p.Custom = custom
+		presets = append(presets, p)
+	}
+	return presets
+}
+
+// SavePresets はプリセットのシートを presets の内容に置き換えます（シートがなければ作成します）
+func (r *SheetsRepository) SavePresets(ctx context.Context, presets []models.Preset) error {
+	values := [][]interface{}{presetHeaders()}
+	for _, p := range presets {
+		custom, err := encodeCustom(p.Custom)
+		if err != nil {
+			return err
+		}
+		row := []interface{}{p.ID, p.Name}
+		for _, f := range models.Fields {
+			row = append(row, f.Value(p.TimeEntry))
+		}
+		row[2+models.Fields[0].Column] = models.NormalizeTime(p.Time)
+		row = append(row, p.Shared, p.Owner, custom, strings.Join(p.Weekdays, ","))
+		values = append(values, row)
+	}
+
+	if err := r.replaceSheetValues(ctx, r.PresetsSheet, values); err != nil {
+		return fmt.Errorf("プリセットの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
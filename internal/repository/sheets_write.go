@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	sheetsv4 "google.golang.org/api/sheets/v4"
+)
+
+// 新しく作成するシートの既定のサイズ（Googleスプレッドシートの既定値と同じ）
+const (
+	defaultSheetRows    = 1000
+	defaultSheetColumns = 26
+)
+
+// replaceSheetValues はシートの A1 以降の値を values で置き換えます（シートがなければ作成します）
+//
+// シートの追加・行や列の拡張・書き込み・余分なセルの消去を1回の BatchUpdate にまとめて送信します。
+// BatchUpdate はすべてのリクエストが成功した場合のみ反映されるため、
+// 失敗した場合はシートの以前の内容がそのまま残ります。
+func (r *SheetsRepository) replaceSheetValues(ctx context.Context, title string, values [][]interface{}) error {
+	spreadsheet, err := r.Service.Spreadsheets.Get(r.spreadsheetID).
+		Fields("sheets.properties").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return fmt.Errorf("シート一覧の取得に失敗しました: %w", classifyAPIError(err))
+	}
+
+	width := 0
+	for _, row := range values {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	var requests []*sheetsv4.Request
+	var sheetID, rowCount, columnCount int64
+	var target *sheetsv4.SheetProperties
+	usedIDs := make(map[int64]bool)
+	for _, sheet := range spreadsheet.Sheets {
+		usedIDs[sheet.Properties.SheetId] = true
+		if sheet.Properties.Title == title {
+			target = sheet.Properties
+		}
+	}
+
+	if target == nil {
+		// 新しいシートを作成（IDを指定して同じ BatchUpdate 内で書き込む）
+		sheetID = newSheetID(usedIDs)
+		rowCount = max(defaultSheetRows, int64(len(values)))
+		columnCount = max(defaultSheetColumns, int64(width))
+		requests = append(requests, &sheetsv4.Request{
+			AddSheet: &sheetsv4.AddSheetRequest{
+				Properties: &sheetsv4.SheetProperties{
+					SheetId: sheetID,
+					Title:   title,
+					GridProperties: &sheetsv4.GridProperties{
+						RowCount:    rowCount,
+						ColumnCount: columnCount,
+					},
+				},
+			},
+		})
+	} else {
+		sheetID = target.SheetId
+		if target.GridProperties != nil {
+			rowCount = target.GridProperties.RowCount
+			columnCount = target.GridProperties.ColumnCount
+		}
+		// 書き込むデータがシートに収まらない場合は行・列を追加する
+		if extra := int64(len(values)) - rowCount; extra > 0 {
+			requests = append(requests, appendDimension(sheetID, "ROWS", extra))
+			rowCount += extra
+		}
+		if extra := int64(width) - columnCount; extra > 0 {
+			requests = append(requests, appendDimension(sheetID, "COLUMNS", extra))
+			columnCount += extra
+		}
+	}
+
+	// 以前の実装（A1:Z のクリア）と同じく Z 列までを置き換え対象とする
+	endColumn := max(int64(width), min(columnCount, defaultSheetColumns))
+
+	rows := make([]*sheetsv4.RowData, len(values))
+	for i, row := range values {
+		cells := make([]*sheetsv4.CellData, len(row))
+		for j, value := range row {
+			cells[j] = toCellData(value)
+		}
+		rows[i] = &sheetsv4.RowData{Values: cells}
+	}
+
+	// range を指定した UpdateCells は、rows に含まれないセルの値を消去する
+	requests = append(requests, &sheetsv4.Request{
+		UpdateCells: &sheetsv4.UpdateCellsRequest{
+			Range: &sheetsv4.GridRange{
+				SheetId:          sheetID,
+				StartRowIndex:    0,
+				EndRowIndex:      rowCount,
+				StartColumnIndex: 0,
+				EndColumnIndex:   endColumn,
+				ForceSendFields:  []string{"SheetId", "StartRowIndex", "StartColumnIndex"},
+			},
+			Rows:   rows,
+			Fields: "userEnteredValue",
+		},
+	})
+
+	_, err = r.Service.Spreadsheets.BatchUpdate(r.spreadsheetID, &sheetsv4.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("データの書き込みに失敗しました: %w", classifyAPIError(err))
+	}
+	return nil
+}
+
+func appendDimension(sheetID int64, dimension string, length int64) *sheetsv4.Request {
+	return &sheetsv4.Request{
+		AppendDimension: &sheetsv4.AppendDimensionRequest{
+			SheetId:         sheetID,
+			Dimension:       dimension,
+			Length:          length,
+			ForceSendFields: []string{"SheetId"},
+		},
+	}
+}
+
+// toCellData は値をセルの入力値に変換します（ValueInputOption RAW と同じく文字列は解釈せずに保存します）
+func toCellData(value interface{}) *sheetsv4.CellData {
+	switch v := value.(type) {
+	case nil:
+		return &sheetsv4.CellData{}
+	case string:
+		if v == "" {
+			return &sheetsv4.CellData{}
+		}
+		return &sheetsv4.CellData{UserEnteredValue: &sheetsv4.ExtendedValue{StringValue: &v}}
+	case float64:
+		return &sheetsv4.CellData{UserEnteredValue: &sheetsv4.ExtendedValue{NumberValue: &v}}
+	case int:
+		f := float64(v)
+		return &sheetsv4.CellData{UserEnteredValue: &sheetsv4.ExtendedValue{NumberValue: &f}}
+	case int64:
+		f := float64(v)
+		return &sheetsv4.CellData{UserEnteredValue: &sheetsv4.ExtendedValue{NumberValue: &f}}
+	case bool:
+		return &sheetsv4.CellData{UserEnteredValue: &sheetsv4.ExtendedValue{BoolValue: &v}}
+	default:
+		s := fmt.Sprintf("%v", v)
+		return &sheetsv4.CellData{UserEnteredValue: &sheetsv4.ExtendedValue{StringValue: &s}}
+	}
+}
+
+// newSheetID は既存のシートと重複しないシートIDを返します
+func newSheetID(used map[int64]bool) int64 {
+	for {
+		id := rand.Int63n(1<<31-1) + 1
+		if !used[id] {
+			return id
+		}
+	}
+}
@@ -0,0 +1,407 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+	"google.golang.org/api/option"
+	sheetsv4 "google.golang.org/api/sheets/v4"
+)
+
+const fakeSpreadsheetID = "test-spreadsheet"
+
+// fakeSheet は偽サーバーのシート1枚です。セルは (行, 列) をキーにした疎な表で保持します
+type fakeSheet struct {
+	id          int64
+	title       string
+	rowCount    int64
+	columnCount int64
+	cells       map[[2]int64]interface{}
+}
+
+func (s *fakeSheet) clone() *fakeSheet {
+	c := *s
+	c.cells = maps.Clone(s.cells)
+	return &c
+}
+
+// fakeSheetsServer は Sheets API v4 のうちリポジトリが使用するエンドポイントを実装した偽サーバーです
+// BatchUpdate は本物と同じく、すべてのリクエストが成功した場合のみ反映します
+type fakeSheetsServer struct {
+	mu     sync.Mutex
+	sheets []*fakeSheet
+
+	// failRequest が true を返したリクエストがあると BatchUpdate 全体をエラーにします
+	failRequest func(index int, req *sheetsv4.Request) bool
+	// dropBatch が true の場合は BatchUpdate の本文を途中まで読んで接続を切ります
+	dropBatch bool
+}
+
+func newFakeSheetsRepository(t *testing.T) (*SheetsRepository, *fakeSheetsServer) {
+	t.Helper()
+	fake := &fakeSheetsServer{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	service, err := sheetsv4.NewService(context.Background(),
+		option.WithHTTPClient(server.Client()),
+		option.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("Sheetsサービスの作成に失敗しました: %v", err)
+	}
+	return NewSheetsRepositoryWithService(service, fakeSpreadsheetID), fake
+}
+
+// snapshot は全シートの複製を返します
+func (f *fakeSheetsServer) snapshot() []*fakeSheet {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sheets := make([]*fakeSheet, len(f.sheets))
+	for i, s := range f.sheets {
+		sheets[i] = s.clone()
+	}
+	return sheets
+}
+
+func (f *fakeSheetsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest, ok := strings.CutPrefix(r.URL.EscapedPath(), "/v4/spreadsheets/"+fakeSpreadsheetID)
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "Requested entity was not found.")
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && rest == "":
+		f.getSpreadsheet(w)
+	case r.Method == http.MethodGet && strings.HasPrefix(rest, "/values/"):
+		rng, err := url.PathUnescape(strings.TrimPrefix(rest, "/values/"))
+		if err != nil {
+			writeFakeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		f.getValues(w, rng)
+	case r.Method == http.MethodPost && rest == ":batchUpdate":
+		f.batchUpdate(w, r)
+	default:
+		writeFakeError(w, http.StatusNotFound, "unsupported: "+r.Method+" "+rest)
+	}
+}
+
+func writeFakeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{"code": status, "message": message},
+	})
+}
+
+func (f *fakeSheetsServer) getSpreadsheet(w http.ResponseWriter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	spreadsheet := &sheetsv4.Spreadsheet{SpreadsheetId: fakeSpreadsheetID}
+	for _, s := range f.sheets {
+		spreadsheet.Sheets = append(spreadsheet.Sheets, &sheetsv4.Sheet{
+			Properties: &sheetsv4.SheetProperties{
+				SheetId: s.id,
+				Title:   s.title,
+				GridProperties: &sheetsv4.GridProperties{
+					RowCount:    s.rowCount,
+					ColumnCount: s.columnCount,
+				},
+			},
+		})
+	}
+	json.NewEncoder(w).Encode(spreadsheet)
+}
+
+// getValues は 'シート名'!A1:I 形式の範囲の値を返します（末尾の空のセル・行は本物と同じく省略します）
+func (f *fakeSheetsServer) getValues(w http.ResponseWriter, rng string) {
+	title, cells, ok := strings.Cut(rng, "!")
+	if !ok {
+		writeFakeError(w, http.StatusBadRequest, "Unable to parse range: "+rng)
+		return
+	}
+	title = strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(title, "'"), "'"), "''", "'")
+	_, end, _ := strings.Cut(cells, ":")
+	lastColumn := columnIndex(strings.TrimRight(end, "0123456789"))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sheet := f.find(title)
+	if sheet == nil {
+		writeFakeError(w, http.StatusBadRequest, "Unable to parse range: "+rng)
+		return
+	}
+
+	var values [][]interface{}
+	for row := int64(0); row < sheet.rowCount; row++ {
+		var line []interface{}
+		for col := int64(0); col <= lastColumn && col < sheet.columnCount; col++ {
+			line = append(line, sheet.cells[[2]int64{row, col}])
+		}
+		for len(line) > 0 && line[len(line)-1] == nil {
+			line = line[:len(line)-1]
+		}
+		for i, v := range line {
+			if v == nil {
+				line[i] = ""
+			}
+		}
+		values = append(values, line)
+	}
+	for len(values) > 0 && len(values[len(values)-1]) == 0 {
+		values = values[:len(values)-1]
+	}
+	json.NewEncoder(w).Encode(&sheetsv4.ValueRange{Range: rng, Values: values})
+}
+
+func (f *fakeSheetsServer) find(title string) *fakeSheet {
+	for _, s := range f.sheets {
+		if s.title == title {
+			return s
+		}
+	}
+	return nil
+}
+
+func (f *fakeSheetsServer) batchUpdate(w http.ResponseWriter, r *http.Request) {
+	if f.dropBatch {
+		// 本文の一部を受け取った時点で接続が切れた状況を再現する
+		io.ReadFull(r.Body, make([]byte, 16))
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return
+	}
+
+	var batch sheetsv4.BatchUpdateSpreadsheetRequest
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		writeFakeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// 複製に順に適用し、すべて成功した場合のみ置き換える
+	working := make([]*fakeSheet, len(f.sheets))
+	for i, s := range f.sheets {
+		working[i] = s.clone()
+	}
+	byID := func(id int64) *fakeSheet {
+		for _, s := range working {
+			if s.id == id {
+				return s
+			}
+		}
+		return nil
+	}
+	for i, req := range batch.Requests {
+		if f.failRequest != nil && f.failRequest(i, req) {
+			writeFakeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid requests[%d]: injected failure", i))
+			return
+		}
+		switch {
+		case req.AddSheet != nil:
+			p := req.AddSheet.Properties
+			working = append(working, &fakeSheet{
+				id:          p.SheetId,
+				title:       p.Title,
+				rowCount:    p.GridProperties.RowCount,
+				columnCount: p.GridProperties.ColumnCount,
+				cells:       map[[2]int64]interface{}{},
+			})
+		case req.AppendDimension != nil:
+			s := byID(req.AppendDimension.SheetId)
+			if s == nil {
+				writeFakeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid requests[%d]: no sheet", i))
+				return
+			}
+			if req.AppendDimension.Dimension == "ROWS" {
+				s.rowCount += req.AppendDimension.Length
+			} else {
+				s.columnCount += req.AppendDimension.Length
+			}
+		case req.UpdateCells != nil:
+			g := req.UpdateCells.Range
+			s := byID(g.SheetId)
+			if s == nil || g.EndRowIndex > s.rowCount || g.EndColumnIndex > s.columnCount {
+				writeFakeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid requests[%d]: range exceeds grid", i))
+				return
+			}
+			for row := g.StartRowIndex; row < g.EndRowIndex; row++ {
+				for col := g.StartColumnIndex; col < g.EndColumnIndex; col++ {
+					key := [2]int64{row, col}
+					delete(s.cells, key)
+					ri, ci := row-g.StartRowIndex, col-g.StartColumnIndex
+					if ri < int64(len(req.UpdateCells.Rows)) && ci < int64(len(req.UpdateCells.Rows[ri].Values)) {
+						if v := cellValue(req.UpdateCells.Rows[ri].Values[ci]); v != nil {
+							s.cells[key] = v
+						}
+					}
+				}
+			}
+		default:
+			writeFakeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid requests[%d]: unsupported", i))
+			return
+		}
+	}
+	f.sheets = working
+	json.NewEncoder(w).Encode(&sheetsv4.BatchUpdateSpreadsheetResponse{SpreadsheetId: fakeSpreadsheetID})
+}
+
+func cellValue(cell *sheetsv4.CellData) interface{} {
+	if cell == nil || cell.UserEnteredValue == nil {
+		return nil
+	}
+	v := cell.UserEnteredValue
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.NumberValue != nil:
+		return *v.NumberValue
+	case v.BoolValue != nil:
+		return *v.BoolValue
+	}
+	return nil
+}
+
+// columnIndex は列名（A, B, ..., AA）を0始まりの位置に変換します
+func columnIndex(name string) int64 {
+	var index int64
+	for _, r := range name {
+		index = index*26 + int64(r-'A'+1)
+	}
+	return index - 1
+}
+
+func testEntries(contents ...string) []models.TimeEntry {
+	entries := make([]models.TimeEntry, len(contents))
+	for i, content := range contents {
+		entries[i] = models.TimeEntry{
+			ID:      fmt.Sprintf("id-%d", i+1),
+			Time:    fmt.Sprintf("%02d:00 - %02d:30", 9+i, 9+i),
+			Content: content,
+			Client:  "A社",
+		}
+	}
+	return entries
+}
+
+func TestSaveTimeEntriesReplacesSheet(t *testing.T) {
+	for _, user := range []string{"", "alice"} {
+		t.Run("user="+user, func(t *testing.T) {
+			repo, _ := newFakeSheetsRepository(t)
+			ctx := WithUser(context.Background(), user)
+			const date = "2026-10-01"
+
+			// 新しいシートの作成と書き込み
+			if _, err := repo.SaveTimeEntries(ctx, date, testEntries("設計", "会議", "レビュー")); err != nil {
+				t.Fatalf("SaveTimeEntries: %v", err)
+			}
+			// 行数が減った場合は以前の行が残らない
+			want := testEntries("設計")
+			if _, err := repo.SaveTimeEntries(ctx, date, want); err != nil {
+				t.Fatalf("SaveTimeEntries: %v", err)
+			}
+			got, err := repo.GetTimeEntries(ctx, date)
+			if err != nil {
+				t.Fatalf("GetTimeEntries: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GetTimeEntries = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSaveTimeEntriesFailureLeavesSheetUntouched(t *testing.T) {
+	const date = "2026-10-01"
+	tests := []struct {
+		name    string
+		prepare func(f *fakeSheetsServer)
+		entries []models.TimeEntry
+	}{
+		{
+			name: "BatchUpdate がエラー",
+			prepare: func(f *fakeSheetsServer) {
+				f.failRequest = func(int, *sheetsv4.Request) bool { return true }
+			},
+			entries: testEntries("変更後"),
+		},
+		{
+			name: "行の追加の後の書き込みでエラー",
+			prepare: func(f *fakeSheetsServer) {
+				// 既存のシートの行数を減らし、AppendDimension が先に送られるようにする
+				f.sheets[0].rowCount = 3
+				f.failRequest = func(_ int, req *sheetsv4.Request) bool { return req.UpdateCells != nil }
+			},
+			entries: testEntries("1", "2", "3", "4", "5"),
+		},
+		{
+			name: "送信中に接続が切れる",
+			prepare: func(f *fakeSheetsServer) {
+				f.dropBatch = true
+			},
+			entries: testEntries("変更後", "変更後2"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, fake := newFakeSheetsRepository(t)
+			ctx := context.Background()
+			original := testEntries("設計", "会議")
+			if _, err := repo.SaveTimeEntries(ctx, date, original); err != nil {
+				t.Fatalf("SaveTimeEntries: %v", err)
+			}
+
+			fake.mu.Lock()
+			tt.prepare(fake)
+			fake.mu.Unlock()
+			before := fake.snapshot()
+
+			if _, err := repo.SaveTimeEntries(ctx, date, tt.entries); err == nil {
+				t.Fatal("SaveTimeEntries: エラーになるはずが成功しました")
+			}
+
+			if after := fake.snapshot(); !reflect.DeepEqual(after, before) {
+				t.Errorf("失敗した保存でシートが変更されました:\nbefore=%+v\nafter=%+v", before[0], after[0])
+			}
+			fake.mu.Lock()
+			fake.failRequest, fake.dropBatch = nil, false
+			fake.mu.Unlock()
+			got, err := repo.GetTimeEntries(ctx, date)
+			if err != nil {
+				t.Fatalf("GetTimeEntries: %v", err)
+			}
+			if !reflect.DeepEqual(got, original) {
+				t.Errorf("GetTimeEntries = %+v, want %+v", got, original)
+			}
+		})
+	}
+}
+
+func TestSaveTimeEntriesFailureDoesNotCreateSheet(t *testing.T) {
+	repo, fake := newFakeSheetsRepository(t)
+	fake.failRequest = func(_ int, req *sheetsv4.Request) bool { return req.UpdateCells != nil }
+
+	ctx := context.Background()
+	if _, err := repo.SaveTimeEntries(ctx, "2026-10-02", testEntries("設計")); err == nil {
+		t.Fatal("SaveTimeEntries: エラーになるはずが成功しました")
+	}
+	if sheets := fake.snapshot(); len(sheets) != 0 {
+		t.Errorf("失敗した保存でシートが作成されました: %+v", sheets[0])
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// GetDayUpdatedAt は ctx のユーザーの from〜to の各日の最終更新日時（time_entries.updated_at の最大値）を返します
+func (r *SQLiteRepository) GetDayUpdatedAt(ctx context.Context, from, to string) (map[string]time.Time, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT date, MAX(updated_at)
+		FROM time_entries
+		WHERE user_id = ? AND date BETWEEN ? AND ?
+		GROUP BY date
+	`, UserFromContext(ctx), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]time.Time)
+	for rows.Next() {
+		var date, updatedAt string
+		if err := rows.Scan(&date, &updatedAt); err != nil {
+			return nil, err
+		}
+		t, err := time.ParseInLocation(sqliteTimeLayout, updatedAt, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		result[date] = t
+	}
+	return result, rows.Err()
+}
+
+// ListSyncStates は ctx のユーザーの from〜to の同期状態を日付ごとに返します
+func (r *SQLiteRepository) ListSyncStates(ctx context.Context, from, to string) (map[string]models.SyncState, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT date, hash, synced_at
+		FROM sync_state
+		WHERE user_id = ? AND date BETWEEN ? AND ?
+	`, UserFromContext(ctx), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]models.SyncState)
+	for rows.Next() {
+		var state models.SyncState
+		var syncedAt string
+		if err := rows.Scan(&state.Date, &state.Hash, &syncedAt); err != nil {
+			return nil, err
+		}
+		state.SyncedAt, err = time.ParseInLocation(sqliteTimeLayout, syncedAt, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		result[state.Date] = state
+	}
+	return result, rows.Err()
+}
+
+// SaveSyncState は ctx のユーザーの日単位の同期状態を保存します
+func (r *SQLiteRepository) SaveSyncState(ctx context.Context, state models.SyncState) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO sync_state (user_id, date, hash, synced_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id, date) DO UPDATE SET hash = excluded.hash, synced_at = excluded.synced_at
+	`, UserFromContext(ctx), state.Date, state.Hash, state.SyncedAt.Format(sqliteTimeLayout))
+	return err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import "context"
+
+type contextKey int
+
+const userKey contextKey = iota
+
+// WithUser はタイムエントリを読み書きするユーザーを ctx に設定します
+// ユーザーが設定されていない（空の）場合は、認証を導入する前と同じ共通のデータを対象にします
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserFromContext は ctx に設定されたユーザーを返します（設定されていない場合は空文字）
+func UserFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(userKey).(string)
+	return user
+}
+
+// ClaimSharedEntries は共通のデータ（認証を導入する前に保存したエントリと同期状態）を user のデータにします
+// user に既にエントリがある日は重複を避けるため移さず、共通のデータのまま残します。移したエントリの件数を返します
+func (r *SQLiteRepository) ClaimSharedEntries(ctx context.Context, user string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE time_entries SET user_id = ?
+		WHERE user_id = '' AND date NOT IN (SELECT date FROM time_entries WHERE user_id = ?)
+	`, user, user)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	claimed, err := res.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE sync_state SET user_id = ?
+		WHERE user_id = '' AND date NOT IN (SELECT date FROM sync_state WHERE user_id = ?)
+	`, user, user)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return claimed, nil
+}
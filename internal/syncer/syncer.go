@@ -0,0 +1,231 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/repository"
+	"github.com/yourusername/timeslice-app/internal/timesheet"
+)
+
+// 同期の方向
+const (
+	DirectionBoth = "both" // 双方向
+	DirectionPush = "push" // ローカル → スプレッドシートのみ
+	DirectionPull = "pull" // スプレッドシート → ローカルのみ
+)
+
+// 競合時の解決方法
+const (
+	ResolveNone   = ""       // 競合を報告するだけで上書きしない
+	ResolveLocal  = "local"  // ローカルの内容で上書きする
+	ResolveRemote = "remote" // スプレッドシートの内容で上書きする
+)
+
+// LocalStore は同期元となるローカルストア（SQLiteRepository）に必要な操作です
+type LocalStore interface {
+	repository.Repository
+	GetDayUpdatedAt(ctx context.Context, from, to string) (map[string]time.Time, error)
+	ListSyncStates(ctx context.Context, from, to string) (map[string]models.SyncState, error)
+	SaveSyncState(ctx context.Context, state models.SyncState) error
+}
+
+// Options は同期の実行条件です
+type Options struct {
+	From      string
+	To        string
+	Direction string
+	Resolve   string
+}
+
+// Conflict は両方で変更されていたため同期しなかった日を表します
+type Conflict struct {
+	Date           string             `json:"date"`
+	LocalUpdatedAt string             `json:"local_updated_at,omitempty"`
+	LastSyncedAt   string             `json:"last_synced_at,omitempty"`
+	Local          []models.TimeEntry `json:"local"`
+	Remote         []models.TimeEntry `json:"remote"`
+}
+
+// Result は同期の結果です
+type Result struct {
+	Pushed    []string   `json:"pushed"`
+	Pulled    []string   `json:"pulled"`
+	Unchanged int        `json:"unchanged"`
+	Conflicts []Conflict `json:"conflicts"`
+	Locked    []string   `json:"locked"` // 提出済み・承認済みのため取り込まなかった日
+}
+
+// Engine はローカルのSQLiteとスプレッドシートの間で日単位の同期を行います
+type Engine struct {
+	local  LocalStore
+	writer repository.Repository // 取り込んだ内容の保存先（省略時は local）
+	remote repository.Repository
+}
+
+func NewEngine(local LocalStore, remote repository.Repository) *Engine {
+	return &Engine{local: local, writer: local, remote: remote}
+}
+
+// SetLocalWriter は取り込んだ内容の保存に使用するリポジトリを設定します
+// 変更履歴やタイムシートでローカルストアをラップしたリポジトリを指定すると、取り込みもそれを介して保存します
+func (e *Engine) SetLocalWriter(writer repository.Repository) {
+	e.writer = writer
+}
+
+// Sync は期間内の各日について変更を検出し、送信・取り込みを行います
+//
+// 前回同期時のハッシュと比較して変更の有無を判定します（updated_at は秒単位のため、
+// 同じ秒内の変更も検出できるよう内容で比較し、updated_at は競合の報告に使用します）。
+// 両方が変更されていて内容が異なる場合は、Resolve が指定されていない限り競合として報告します。
+func (e *Engine) Sync(ctx context.Context, opts Options) (*Result, error) {
+	if opts.Direction == "" {
+		opts.Direction = DirectionBoth
+	}
+	switch opts.Direction {
+	case DirectionBoth, DirectionPush, DirectionPull:
+	default:
+		return nil, fmt.Errorf("同期の方向が不正です: %s", opts.Direction)
+	}
+	switch opts.Resolve {
+	case ResolveNone, ResolveLocal, ResolveRemote:
+	default:
+		return nil, fmt.Errorf("競合の解決方法が不正です: %s", opts.Resolve)
+	}
+
+	localEntries, err := e.local.GetTimeEntriesRange(ctx, opts.From, opts.To)
+	if err != nil {
+		return nil, fmt.Errorf("ローカルデータの取得に失敗しました: %v", err)
+	}
+	remoteEntries, err := e.remote.GetTimeEntriesRange(ctx, opts.From, opts.To)
+	if err != nil {
+		return nil, fmt.Errorf("スプレッドシートのデータの取得に失敗しました: %v", err)
+	}
+	updatedAt, err := e.local.GetDayUpdatedAt(ctx, opts.From, opts.To)
+	if err != nil {
+		return nil, fmt.Errorf("更新日時の取得に失敗しました: %v", err)
+	}
+	states, err := e.local.ListSyncStates(ctx, opts.From, opts.To)
+	if err != nil {
+		return nil, fmt.Errorf("同期状態の取得に失敗しました: %v", err)
+	}
+
+	// 対象日: どちらかにデータがある日と、過去に同期した日（削除の反映のため）
+	dateSet := make(map[string]bool)
+	for date := range localEntries {
+		dateSet[date] = true
+	}
+	for date := range remoteEntries {
+		dateSet[date] = true
+	}
+	for date := range states {
+		dateSet[date] = true
+	}
+	dates := make([]string, 0, len(dateSet))
+	for date := range dateSet {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	result := &Result{Pushed: []string{}, Pulled: []string{}, Conflicts: []Conflict{}, Locked: []string{}}
+	for _, date := range dates {
+		local := localEntries[date]
+		remote := remoteEntries[date]
+		localHash := models.EntriesHash(local)
+		remoteHash := models.EntriesHash(remote)
+		state, synced := states[date]
+
+		if localHash == remoteHash {
+			result.Unchanged++
+			if !synced || state.Hash != localHash {
+				if err := e.saveState(ctx, date, localHash, time.Now()); err != nil {
+					return result, err
+				}
+			}
+			continue
+		}
+
+		var localChanged, remoteChanged bool
+		if synced {
+			localChanged = localHash != state.Hash
+			remoteChanged = remoteHash != state.Hash
+		} else {
+			// 初回同期: 片方にしかデータがない場合はもう一方へ複製する
+			localChanged = len(local) > 0
+			remoteChanged = len(remote) > 0
+		}
+
+		var action string
+		switch {
+		case localChanged && remoteChanged:
+			action = opts.Resolve
+		case localChanged:
+			action = ResolveLocal
+		case remoteChanged:
+			action = ResolveRemote
+		default:
+			result.Unchanged++
+			continue
+		}
+		// 指定された方向以外への反映は行わない
+		if action == ResolveLocal && opts.Direction == DirectionPull ||
+			action == ResolveRemote && opts.Direction == DirectionPush {
+			continue
+		}
+
+		switch action {
+		case ResolveLocal:
+			if _, err := e.remote.SaveTimeEntries(ctx, date, local); err != nil {
+				return result, fmt.Errorf("%s の送信に失敗しました: %v", date, err)
+			}
+			if err := e.saveState(ctx, date, localHash, time.Now()); err != nil {
+				return result, err
+			}
+			result.Pushed = append(result.Pushed, date)
+		case ResolveRemote:
+			savedAt, err := e.writer.SaveTimeEntries(ctx, date, remote)
+			if errors.Is(err, timesheet.ErrLocked) {
+				// 同期状態は更新せず、提出が差し戻された後の同期で取り込む
+				result.Locked = append(result.Locked, date)
+				continue
+			}
+			if err != nil {
+				return result, fmt.Errorf("%s の取り込みに失敗しました: %v", date, err)
+			}
+			if err := e.saveState(ctx, date, remoteHash, savedAt); err != nil {
+				return result, err
+			}
+			result.Pulled = append(result.Pulled, date)
+		default:
+			conflict := Conflict{Date: date, Local: nonNil(local), Remote: nonNil(remote)}
+			if t, ok := updatedAt[date]; ok {
+				conflict.LocalUpdatedAt = t.Format("2006/01/02 15:04:05")
+			}
+			if synced {
+				conflict.LastSyncedAt = state.SyncedAt.Format("2006/01/02 15:04:05")
+			}
+			result.Conflicts = append(result.Conflicts, conflict)
+		}
+	}
+
+	return result, nil
+}
+
+func (e *Engine) saveState(ctx context.Context, date, hash string, syncedAt time.Time) error {
+	err := e.local.SaveSyncState(ctx, models.SyncState{Date: date, Hash: hash, SyncedAt: syncedAt})
+	if err != nil {
+		return fmt.Errorf("%s の同期状態の保存に失敗しました: %v", date, err)
+	}
+	return nil
+}
+
+func nonNil(entries []models.TimeEntry) []models.TimeEntry {
+	if entries == nil {
+		return []models.TimeEntry{}
+	}
+	return entries
+}
@@ -0,0 +1,37 @@
+package timesheet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+// Repository は提出済み・承認済みのタイムシートの期間に含まれる日の保存を ErrLocked で拒否するリポジトリです
+type Repository struct {
+	repository.Repository
+	store *Store
+}
+
+func NewRepository(repo repository.Repository, store *Store) *Repository {
+	return &Repository{Repository: repo, store: store}
+}
+
+// SaveTimeEntries は ctx のユーザーの date が提出済み・承認済みでない場合のみ保存します
+// 確認から保存までの間はユーザーのタイムシートの状態を変更できません（Store.Apply と排他します）
+func (r *Repository) SaveTimeEntries(ctx context.Context, date string, entries []models.TimeEntry) (time.Time, error) {
+	user := repository.UserFromContext(ctx)
+	unlock := r.store.lockUser(user)
+	defer unlock()
+
+	locking, err := r.store.Locking(ctx, user, date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if locking != nil {
+		return time.Time{}, fmt.Errorf("%w（%s は%s）", ErrLocked, locking.ID, stateLabels[locking.State])
+	}
+	return r.Repository.SaveTimeEntries(ctx, date, entries)
+}
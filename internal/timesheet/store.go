@@ -0,0 +1,240 @@
+package timesheet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// userLockStripes はユーザーごとの排他に使用するロックの数です
+const userLockStripes = 64
+
+// Store はタイムシートの状態と遷移の記録をSQLiteに保存します
+// 遷移の記録の更新・削除はトリガーで禁止しており、追記のみが可能です
+type Store struct {
+	db *sql.DB
+	// userLocks は状態の変更とエントリの保存をユーザーごとに排他します（ユーザー名のハッシュで選びます）
+	userLocks [userLockStripes]sync.Mutex
+}
+
+// Open はタイムシートのデータベースを開きます（存在しない場合は作成します）
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS timesheets (
+			user_id TEXT NOT NULL,
+			period TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			from_date TEXT NOT NULL,
+			to_date TEXT NOT NULL,
+			state TEXT NOT NULL,
+			comment TEXT NOT NULL DEFAULT '',
+			updated_by TEXT NOT NULL DEFAULT '',
+			updated_at TEXT NOT NULL,
+			PRIMARY KEY (user_id, period)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_timesheets_user_dates ON timesheets (user_id, from_date, to_date);
+
+		CREATE TABLE IF NOT EXISTS timesheet_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			period TEXT NOT NULL,
+			action TEXT NOT NULL,
+			from_state TEXT NOT NULL,
+			to_state TEXT NOT NULL,
+			actor TEXT NOT NULL DEFAULT '',
+			comment TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_timesheet_events_user_period ON timesheet_events (user_id, period, id);
+
+		CREATE TRIGGER IF NOT EXISTS timesheet_events_no_update BEFORE UPDATE ON timesheet_events
+		BEGIN
+			SELECT RAISE(ABORT, 'timesheet_events is append-only');
+		END;
+
+		CREATE TRIGGER IF NOT EXISTS timesheet_events_no_delete BEFORE DELETE ON timesheet_events
+		BEGIN
+			SELECT RAISE(ABORT, 'timesheet_events is append-only');
+		END;
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("タイムシートのデータベースの作成に失敗しました: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close はデータベースを閉じます
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Get は user の期間のタイムシートを遷移の記録とともに返します（記録がない場合は下書き）
+func (s *Store) Get(ctx context.Context, user string, period Period) (*Timesheet, error) {
+	sheet := &Timesheet{User: user, Period: period, State: StateDraft}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT state, comment, updated_by, updated_at FROM timesheets WHERE user_id = ? AND period = ?
+	`, user, period.ID).Scan(&sheet.State, &sheet.Comment, &sheet.UpdatedBy, &sheet.UpdatedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, action, from_state, to_state, actor, comment, created_at
+		FROM timesheet_events WHERE user_id = ? AND period = ? ORDER BY id
+	`, user, period.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sheet.History = []Event{}
+	for rows.Next() {
+		var event Event
+		if err := rows.Scan(&event.ID, &event.Action, &event.From, &event.To, &event.Actor, &event.Comment, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		sheet.History = append(sheet.History, event)
+	}
+	return sheet, rows.Err()
+}
+
+// List は users のタイムシートを期間の新しい順に返します（遷移の記録は含みません）
+// state を指定した場合はその状態のもののみを返します
+func (s *Store) List(ctx context.Context, users []string, state State) ([]Timesheet, error) {
+	sheets := []Timesheet{}
+	if len(users) == 0 {
+		return sheets, nil
+	}
+
+	query := `
+		SELECT user_id, period, kind, from_date, to_date, state, comment, updated_by, updated_at
+		FROM timesheets WHERE user_id IN (?` + strings.Repeat(", ?", len(users)-1) + `)`
+	args := make([]any, 0, len(users)+1)
+	for _, user := range users {
+		args = append(args, user)
+	}
+	if state != "" {
+		query += ` AND state = ?`
+		args = append(args, string(state))
+	}
+	query += ` ORDER BY from_date DESC, kind, user_id`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sheet Timesheet
+		err := rows.Scan(&sheet.User, &sheet.ID, &sheet.Kind, &sheet.From, &sheet.To,
+			&sheet.State, &sheet.Comment, &sheet.UpdatedBy, &sheet.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets, rows.Err()
+}
+
+// Apply は user の期間のタイムシートに action を行い、遷移を記録します
+// byApprover は承認者としての操作かどうかで、状態の確認と同じトランザクション内で NextBy により検証します
+// 操作できない状態の場合は ErrInvalidTransition、本人が承認者のみの操作を行った場合は ErrApproverRequired、
+// 理由のない差し戻しは ErrCommentRequired を返します
+func (s *Store) Apply(ctx context.Context, user string, period Period, action Action, actor, comment string, byApprover bool) (*Timesheet, error) {
+	comment = strings.TrimSpace(comment)
+	if action == ActionReject && comment == "" {
+		return nil, ErrCommentRequired
+	}
+
+	unlock := s.lockUser(user)
+	defer unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	current := StateDraft
+	err = tx.QueryRowContext(ctx, `SELECT state FROM timesheets WHERE user_id = ? AND period = ?`, user, period.ID).Scan(&current)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		tx.Rollback()
+		return nil, err
+	}
+	next, err := NextBy(current, action, byApprover)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	now := time.Now().Format(timeLayout)
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO timesheets (user_id, period, kind, from_date, to_date, state, comment, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, period) DO UPDATE SET
+			state = excluded.state, comment = excluded.comment,
+			updated_by = excluded.updated_by, updated_at = excluded.updated_at
+	`, user, period.ID, period.Kind, period.From, period.To, string(next), comment, actor, now)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO timesheet_events (user_id, period, action, from_state, to_state, actor, comment, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, user, period.ID, string(action), string(current), string(next), actor, comment, now)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, user, period)
+}
+
+// lockUser は user の状態の変更とエントリの保存を排他し、解除する関数を返します
+func (s *Store) lockUser(user string) func() {
+	h := fnv.New32a()
+	h.Write([]byte(user))
+	mu := &s.userLocks[h.Sum32()%userLockStripes]
+	mu.Lock()
+	return mu.Unlock
+}
+
+// Locking は user の date を含む提出済み・承認済みのタイムシートを返します（ない場合は nil）
+func (s *Store) Locking(ctx context.Context, user, date string) (*Timesheet, error) {
+	sheet := &Timesheet{User: user}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT period, kind, from_date, to_date, state FROM timesheets
+		WHERE user_id = ? AND from_date <= ? AND to_date >= ? AND state IN (?, ?)
+		ORDER BY from_date LIMIT 1
+	`, user, date, date, string(StateSubmitted), string(StateApproved)).Scan(
+		&sheet.ID, &sheet.Kind, &sheet.From, &sheet.To, &sheet.State)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sheet, nil
+}
@@ -0,0 +1,157 @@
+package timesheet
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+	"github.com/yourusername/timeslice-app/internal/repository"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "timesheets.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestStoreApply(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+	period, err := ParsePeriod("2026-W42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		action     Action
+		actor      string
+		comment    string
+		byApprover bool
+		want       State
+		wantErr    error
+	}{
+		{action: ActionSubmit, actor: "alice", want: StateSubmitted},
+		{action: ActionReject, actor: "bob", byApprover: true, wantErr: ErrCommentRequired},
+		{action: ActionReject, actor: "bob", comment: "  金曜日の内容を確認してください ", byApprover: true, want: StateRejected},
+		{action: ActionApprove, actor: "bob", byApprover: true, wantErr: ErrInvalidTransition},
+		{action: ActionSubmit, actor: "alice", want: StateSubmitted},
+		{action: ActionApprove, actor: "alice", wantErr: ErrApproverRequired},
+		{action: ActionApprove, actor: "bob", byApprover: true, want: StateApproved},
+		{action: ActionReopen, actor: "alice", wantErr: ErrApproverRequired},
+		{action: ActionReopen, actor: "bob", byApprover: true, want: StateDraft},
+	}
+
+	var events int
+	for i, step := range steps {
+		sheet, err := store.Apply(ctx, "alice", period, step.action, step.actor, step.comment, step.byApprover)
+		if step.wantErr != nil {
+			if !errors.Is(err, step.wantErr) {
+				t.Fatalf("手順 %d (%s): err = %v, want %v", i+1, step.action, err, step.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("手順 %d (%s): %v", i+1, step.action, err)
+		}
+		events++
+		if sheet.State != step.want || sheet.UpdatedBy != step.actor || len(sheet.History) != events {
+			t.Fatalf("手順 %d (%s): state=%s updated_by=%s history=%d, want %s, %s, %d",
+				i+1, step.action, sheet.State, sheet.UpdatedBy, len(sheet.History), step.want, step.actor, events)
+		}
+	}
+
+	sheet, err := store.Get(ctx, "alice", period)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 失敗した操作は記録しない
+	wantHistory := []struct {
+		action   Action
+		from, to State
+	}{
+		{ActionSubmit, StateDraft, StateSubmitted},
+		{ActionReject, StateSubmitted, StateRejected},
+		{ActionSubmit, StateRejected, StateSubmitted},
+		{ActionApprove, StateSubmitted, StateApproved},
+		{ActionReopen, StateApproved, StateDraft},
+	}
+	if len(sheet.History) != len(wantHistory) {
+		t.Fatalf("遷移の記録 = %+v", sheet.History)
+	}
+	for i, want := range wantHistory {
+		got := sheet.History[i]
+		if got.Action != want.action || got.From != want.from || got.To != want.to {
+			t.Errorf("遷移の記録 %d = %s %s→%s, want %s %s→%s", i, got.Action, got.From, got.To, want.action, want.from, want.to)
+		}
+	}
+	if c := sheet.History[1].Comment; c != "金曜日の内容を確認してください" {
+		t.Errorf("差し戻しの理由 = %q", c)
+	}
+
+	// 遷移の記録は変更できない
+	if _, err := store.db.Exec(`UPDATE timesheet_events SET actor = 'mallory'`); err == nil {
+		t.Error("遷移の記録を更新できました")
+	}
+	if _, err := store.db.Exec(`DELETE FROM timesheet_events`); err == nil {
+		t.Error("遷移の記録を削除できました")
+	}
+}
+
+func TestRepositoryRejectsLockedDays(t *testing.T) {
+	ctx := repository.WithUser(context.Background(), "alice")
+	store := openTestStore(t)
+	local, err := repository.NewSQLiteRepository(filepath.Join(t.TempDir(), "entries.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer local.Close()
+	repo := NewRepository(local, store)
+
+	week, _ := ParsePeriod("2026-W42") // 2026-10-12〜2026-10-18
+	entries := []models.TimeEntry{{Time: "30", Content: "設計"}}
+	save := func(ctx context.Context, date string) error {
+		_, err := repo.SaveTimeEntries(ctx, date, entries)
+		return err
+	}
+
+	if err := save(ctx, "2026-10-14"); err != nil {
+		t.Fatalf("下書きの期間の保存: %v", err)
+	}
+	if _, err := store.Apply(ctx, "alice", week, ActionSubmit, "alice", "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		date   string
+		locked bool
+	}{
+		{name: "提出した週の日", ctx: ctx, date: "2026-10-14", locked: true},
+		{name: "提出した週の最終日", ctx: ctx, date: "2026-10-18", locked: true},
+		{name: "翌週の日", ctx: ctx, date: "2026-10-19"},
+		{name: "他のユーザー", ctx: repository.WithUser(context.Background(), "bob"), date: "2026-10-14"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := save(tt.ctx, tt.date)
+			if tt.locked != errors.Is(err, ErrLocked) || !tt.locked && err != nil {
+				t.Errorf("保存 = %v, want locked=%v", err, tt.locked)
+			}
+		})
+	}
+
+	// 差し戻し後は保存できる
+	if _, err := store.Apply(ctx, "alice", week, ActionReject, "bob", "修正してください", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := save(ctx, "2026-10-14"); err != nil {
+		t.Errorf("差し戻し後の保存: %v", err)
+	}
+}
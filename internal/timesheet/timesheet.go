@@ -0,0 +1,197 @@
+// Package timesheet は週・月単位のタイムシートの提出と承認を管理します
+// 状態は 下書き → 提出済み → 承認済み／差し戻し と遷移し、遷移はすべて記録します
+// 提出済み・承認済みの期間の日はエントリを保存できません（Repository を参照）
+package timesheet
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// エラー
+var (
+	ErrLocked            = errors.New("提出済みまたは承認済みの期間のためエントリを変更できません")
+	ErrInvalidTransition = errors.New("タイムシートの状態を変更できません")
+	ErrCommentRequired   = errors.New("差し戻しの理由（comment）を入力してください")
+	ErrApproverRequired  = errors.New("承認者のみが行える操作です")
+)
+
+// State はタイムシートの状態です
+type State string
+
+// 状態
+const (
+	StateDraft     State = "draft"     // 下書き（記録がない期間も下書きとして扱う）
+	StateSubmitted State = "submitted" // 提出済み（エントリは変更できない）
+	StateApproved  State = "approved"  // 承認済み（エントリは変更できない）
+	StateRejected  State = "rejected"  // 差し戻し（修正して再提出する）
+)
+
+// stateLabels はエラーメッセージに使用する状態の名前です
+var stateLabels = map[State]string{
+	StateDraft:     "下書き",
+	StateSubmitted: "提出済み",
+	StateApproved:  "承認済み",
+	StateRejected:  "差し戻し",
+}
+
+// Locked は状態がエントリの変更を禁止するかどうかを返します
+func (s State) Locked() bool {
+	return s == StateSubmitted || s == StateApproved
+}
+
+// ParseState は状態の名前を検証して返します
+func ParseState(s string) (State, error) {
+	state := State(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := stateLabels[state]; !ok {
+		return "", fmt.Errorf("状態は draft, submitted, approved, rejected のいずれかを指定してください: %q", s)
+	}
+	return state, nil
+}
+
+// Action はタイムシートの状態を変更する操作です
+type Action string
+
+// 操作
+const (
+	ActionSubmit  Action = "submit"  // 本人が提出する
+	ActionApprove Action = "approve" // 承認者が承認する
+	ActionReject  Action = "reject"  // 承認者が理由を付けて差し戻す
+	ActionReopen  Action = "reopen"  // 下書きに戻す（提出の取り下げ、承認の取り消し）
+)
+
+// actionLabels はエラーメッセージに使用する操作の名前です
+var actionLabels = map[Action]string{
+	ActionSubmit:  "提出",
+	ActionApprove: "承認",
+	ActionReject:  "差し戻し",
+	ActionReopen:  "下書きに戻す操作",
+}
+
+// transitions は操作ごとの遷移元の状態と遷移先の状態です
+var transitions = map[Action]struct {
+	from []State
+	to   State
+}{
+	ActionSubmit:  {from: []State{StateDraft, StateRejected}, to: StateSubmitted},
+	ActionApprove: {from: []State{StateSubmitted}, to: StateApproved},
+	ActionReject:  {from: []State{StateSubmitted}, to: StateRejected},
+	ActionReopen:  {from: []State{StateSubmitted, StateApproved, StateRejected}, to: StateDraft},
+}
+
+// approverOnly は操作ごとに承認者のみが行える遷移元の状態です
+// 本人は承認・差し戻しができず、承認済みのタイムシートを下書きに戻すこともできません
+var approverOnly = map[Action][]State{
+	ActionApprove: {StateSubmitted},
+	ActionReject:  {StateSubmitted},
+	ActionReopen:  {StateApproved},
+}
+
+// NextBy は Next と同じく遷移後の状態を返します
+// byApprover が false（本人の操作）の場合、承認者のみが行える遷移は ErrApproverRequired を返します
+func NextBy(from State, action Action, byApprover bool) (State, error) {
+	next, err := Next(from, action)
+	if err != nil || byApprover {
+		return next, err
+	}
+	for _, s := range approverOnly[action] {
+		if s == from {
+			return "", fmt.Errorf("%w: %sのタイムシートの%s", ErrApproverRequired, stateLabels[from], actionLabels[action])
+		}
+	}
+	return next, nil
+}
+
+// Next は from の状態に action を行った後の状態を返します
+func Next(from State, action Action) (State, error) {
+	t, ok := transitions[action]
+	if !ok {
+		return "", fmt.Errorf("%w: 不明な操作です: %s", ErrInvalidTransition, action)
+	}
+	for _, s := range t.from {
+		if s == from {
+			return t.to, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %sのタイムシートは%sできません", ErrInvalidTransition, stateLabels[from], actionLabels[action])
+}
+
+// 期間の種類
+const (
+	KindWeek  = "week"  // 月曜日から日曜日（ISO 8601 の週）
+	KindMonth = "month" // 1日から末日
+)
+
+const dateLayout = "2006-01-02"
+
+// Period は提出の単位となる期間です
+type Period struct {
+	ID   string `json:"period"` // 2026-W42（週）または 2026-10（月）
+	Kind string `json:"kind"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ParsePeriod は 2026-W42（週）または 2026-10（月）形式の期間を解釈します
+func ParsePeriod(s string) (Period, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if year, week, ok := strings.Cut(s, "-W"); ok {
+		y, yerr := strconv.Atoi(year)
+		w, werr := strconv.Atoi(week)
+		if yerr != nil || werr != nil || len(year) != 4 || len(week) != 2 {
+			return Period{}, periodError(s)
+		}
+		// 1月4日を含む週がその年の第1週
+		jan4 := time.Date(y, time.January, 4, 0, 0, 0, 0, time.UTC)
+		monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(w-1)*7)
+		if iy, iw := monday.ISOWeek(); iy != y || iw != w {
+			return Period{}, periodError(s)
+		}
+		return Period{
+			ID:   fmt.Sprintf("%04d-W%02d", y, w),
+			Kind: KindWeek,
+			From: monday.Format(dateLayout),
+			To:   monday.AddDate(0, 0, 6).Format(dateLayout),
+		}, nil
+	}
+
+	first, err := time.Parse("2006-01", s)
+	if err != nil {
+		return Period{}, periodError(s)
+	}
+	return Period{
+		ID:   first.Format("2006-01"),
+		Kind: KindMonth,
+		From: first.Format(dateLayout),
+		To:   first.AddDate(0, 1, -1).Format(dateLayout),
+	}, nil
+}
+
+func periodError(s string) error {
+	return fmt.Errorf("期間の形式が正しくありません（週は 2026-W42、月は 2026-10）: %q", s)
+}
+
+// Timesheet はユーザーの1期間のタイムシートです
+type Timesheet struct {
+	User string `json:"user"`
+	Period
+	State     State   `json:"state"`
+	Comment   string  `json:"comment,omitempty"` // 最後の操作のコメント（差し戻しの理由など）
+	UpdatedBy string  `json:"updated_by,omitempty"`
+	UpdatedAt string  `json:"updated_at,omitempty"`
+	History   []Event `json:"history,omitempty"`
+}
+
+// Event は状態の遷移の記録です
+type Event struct {
+	ID        int64  `json:"id"`
+	Action    Action `json:"action"`
+	From      State  `json:"from"`
+	To        State  `json:"to"`
+	Actor     string `json:"actor"`
+	Comment   string `json:"comment,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
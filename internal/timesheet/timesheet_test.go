@@ -0,0 +1,100 @@
+package timesheet
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNextBy(t *testing.T) {
+	tests := []struct {
+		from       State
+		action     Action
+		byApprover bool
+		want       State
+		wantErr    error
+	}{
+		// 本人の操作
+		{from: StateDraft, action: ActionSubmit, want: StateSubmitted},
+		{from: StateRejected, action: ActionSubmit, want: StateSubmitted},
+		{from: StateSubmitted, action: ActionSubmit, wantErr: ErrInvalidTransition},
+		{from: StateApproved, action: ActionSubmit, wantErr: ErrInvalidTransition},
+		{from: StateSubmitted, action: ActionReopen, want: StateDraft},
+		{from: StateRejected, action: ActionReopen, want: StateDraft},
+		{from: StateApproved, action: ActionReopen, wantErr: ErrApproverRequired},
+		{from: StateDraft, action: ActionReopen, wantErr: ErrInvalidTransition},
+		{from: StateSubmitted, action: ActionApprove, wantErr: ErrApproverRequired},
+		{from: StateSubmitted, action: ActionReject, wantErr: ErrApproverRequired},
+		{from: StateDraft, action: ActionApprove, wantErr: ErrInvalidTransition},
+
+		// 承認者の操作
+		{from: StateSubmitted, action: ActionApprove, byApprover: true, want: StateApproved},
+		{from: StateSubmitted, action: ActionReject, byApprover: true, want: StateRejected},
+		{from: StateApproved, action: ActionReopen, byApprover: true, want: StateDraft},
+		{from: StateDraft, action: ActionApprove, byApprover: true, wantErr: ErrInvalidTransition},
+		{from: StateRejected, action: ActionApprove, byApprover: true, wantErr: ErrInvalidTransition},
+		{from: StateApproved, action: ActionReject, byApprover: true, wantErr: ErrInvalidTransition},
+		{from: StateDraft, action: "delete", byApprover: true, wantErr: ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		name := string(tt.from) + "/" + string(tt.action)
+		if tt.byApprover {
+			name += "/approver"
+		}
+		t.Run(name, func(t *testing.T) {
+			got, err := NextBy(tt.from, tt.action, tt.byApprover)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("NextBy = %q, %v, want error %v", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NextBy = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStateLocked(t *testing.T) {
+	locked := map[State]bool{StateDraft: false, StateSubmitted: true, StateApproved: true, StateRejected: false}
+	for state, want := range locked {
+		if got := state.Locked(); got != want {
+			t.Errorf("%s.Locked() = %v, want %v", state, got, want)
+		}
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Period
+		wantErr bool
+	}{
+		{in: "2026-W42", want: Period{ID: "2026-W42", Kind: KindWeek, From: "2026-10-12", To: "2026-10-18"}},
+		{in: "2026-w01", want: Period{ID: "2026-W01", Kind: KindWeek, From: "2025-12-29", To: "2026-01-04"}},
+		{in: "2026-W53", want: Period{ID: "2026-W53", Kind: KindWeek, From: "2026-12-28", To: "2027-01-03"}},
+		{in: "2026-10", want: Period{ID: "2026-10", Kind: KindMonth, From: "2026-10-01", To: "2026-10-31"}},
+		{in: " 2028-02 ", want: Period{ID: "2028-02", Kind: KindMonth, From: "2028-02-01", To: "2028-02-29"}},
+		{in: "2025-W53", wantErr: true}, // 2025年は52週まで
+		{in: "2026-W1", wantErr: true},
+		{in: "2026-13", wantErr: true},
+		{in: "2026-10-01", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePeriod(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParsePeriod(%q) = %+v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParsePeriod(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
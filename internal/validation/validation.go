@@ -0,0 +1,201 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/timeslice-app/internal/models"
+)
+
+// DayIndex は特定の行ではなく日全体に対するエラーであることを示すインデックスです
+const DayIndex = -1
+
+// DefaultMaxDailyMinutes は1日に記録できる合計時間の上限（分）です
+const DefaultMaxDailyMinutes = 24 * 60
+
+// FieldError は1つの行・項目に対する検証エラーを表します
+type FieldError struct {
+	Index   int    `json:"index"` // 送信された配列内の位置（0始まり、日全体の場合は -1）
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors は検証エラーの一覧です
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		if fe.Index == DayIndex {
+			messages[i] = fmt.Sprintf("%s: %s", fe.Field, fe.Message)
+		} else {
+			messages[i] = fmt.Sprintf("行 %d %s: %s", fe.Index+1, fe.Field, fe.Message)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validator はタイムエントリの検証ルールを保持します
+type Validator struct {
+	// MaxDailyMinutes は1日の合計時間の上限です（0以下の場合はチェックしない）
+	MaxDailyMinutes int
+	// MasterDataFields は業務データベースに登録済みの値のみを許可する項目です
+	MasterDataFields []string
+	// CustomFields は設定で追加された項目です（select の値は業務データベースと照合します）
+	CustomFields []models.CustomField
+}
+
+// NewValidator は既定のルールで Validator を作成します
+func NewValidator() *Validator {
+	return &Validator{
+		MaxDailyMinutes:  DefaultMaxDailyMinutes,
+		MasterDataFields: []string{models.FieldClient, models.FieldPurpose, models.FieldAction, models.FieldWith, models.FieldPcCc},
+	}
+}
+
+// fieldValue はエントリから項目の値を取り出します
+func fieldValue(entry models.TimeEntry, key string) string {
+	field, ok := models.LookupField(key)
+	if !ok {
+		return ""
+	}
+	return field.Value(entry)
+}
+
+// Validate はエントリを検証し、エラーがあれば行・項目ごとに返します
+// items が nil の場合は業務データベースとの照合を行いません
+func (v *Validator) Validate(entries []models.TimeEntry, items []models.DbItem) Errors {
+	var errs Errors
+
+	// 業務データベースの値を項目ごとにまとめる
+	var known map[string]map[string]bool
+	if items != nil {
+		known = make(map[string]map[string]bool)
+		for _, item := range items {
+			itemType := models.NormalizeFieldKey(item.Type)
+			if known[itemType] == nil {
+				known[itemType] = make(map[string]bool)
+			}
+			known[itemType][item.Value] = true
+		}
+	}
+
+	totalMinutes := 0
+	for i, entry := range entries {
+		if strings.TrimSpace(entry.Time) == "" {
+			errs = append(errs, FieldError{Index: i, Field: "time", Message: "時間は必須です"})
+		} else if slot, err := entry.Slot(); err != nil {
+			errs = append(errs, FieldError{Index: i, Field: "time", Message: err.Error()})
+		} else if slot.Start != "" && !slot.HasRange() {
+			errs = append(errs, FieldError{Index: i, Field: "time", Message: "開始時刻のみでは所要時間がわかりません（09:00 - 09:30 のように終了時刻も指定してください）"})
+		} else if slot.Minutes <= 0 {
+			errs = append(errs, FieldError{Index: i, Field: "time", Message: "時間は0分より大きい値を指定してください"})
+		} else {
+			totalMinutes += slot.Minutes
+		}
+
+		if strings.TrimSpace(entry.Content) == "" {
+			errs = append(errs, FieldError{Index: i, Field: "content", Message: "内容は必須です"})
+		}
+
+		for _, field := range v.MasterDataFields {
+			value := fieldValue(entry, field)
+			values, ok := known[field]
+			// 業務データベースに項目自体が未登録の場合は照合しない
+			if value == "" || !ok || len(values) == 0 {
+				continue
+			}
+			if !values[value] {
+				errs = append(errs, FieldError{
+					Index:   i,
+					Field:   field,
+					Message: fmt.Sprintf("業務データベースに登録されていない値です: %s", value),
+				})
+			}
+		}
+
+		errs = append(errs, v.validateCustom(i, entry, known)...)
+	}
+
+	if v.MaxDailyMinutes > 0 && totalMinutes > v.MaxDailyMinutes {
+		errs = append(errs, FieldError{
+			Index:   DayIndex,
+			Field:   "time",
+			Message: fmt.Sprintf("1日の合計時間（%d分）が上限（%d分）を超えています", totalMinutes, v.MaxDailyMinutes),
+		})
+	}
+
+	return errs
+}
+
+// validateCustom はエントリのカスタム項目の値を検証します
+func (v *Validator) validateCustom(i int, entry models.TimeEntry, known map[string]map[string]bool) Errors {
+	var errs Errors
+
+	keys := make([]string, 0, len(entry.Custom))
+	for key := range entry.Custom {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var field *models.CustomField
+		for j := range v.CustomFields {
+			if v.CustomFields[j].Key == key {
+				field = &v.CustomFields[j]
+				break
+			}
+		}
+		if field == nil {
+			errs = append(errs, FieldError{Index: i, Field: key, Message: "定義されていない項目です"})
+			continue
+		}
+
+		value, err := field.Parse(entry.Custom[key])
+		if err != nil {
+			errs = append(errs, FieldError{Index: i, Field: key, Message: err.Error()})
+			continue
+		}
+		if field.Type != models.CustomSelect || value == nil {
+			continue
+		}
+
+		// 選択肢は業務データベースの種別がキーまたは見出しと一致する値です
+		values := known[field.Key]
+		if len(values) == 0 {
+			values = known[models.NormalizeFieldKey(field.DisplayLabel())]
+		}
+		if len(values) > 0 && !values[value.(string)] {
+			errs = append(errs, FieldError{
+				Index:   i,
+				Field:   key,
+				Message: fmt.Sprintf("業務データベースに登録されていない値です: %s", value),
+			})
+		}
+	}
+	return errs
+}
+
+// ValidatePresets はプリセットを検証します
+// プリセットは一部の項目のみを埋めたひな形のため、名前以外は入力された値のみを検証します
+func (v *Validator) ValidatePresets(presets []models.Preset) Errors {
+	var errs Errors
+	for i, p := range presets {
+		if strings.TrimSpace(p.Name) == "" {
+			errs = append(errs, FieldError{Index: i, Field: "name", Message: "名前は必須です"})
+		}
+		if strings.TrimSpace(p.Time) != "" {
+			if _, err := p.Slot(); err != nil {
+				errs = append(errs, FieldError{Index: i, Field: "time", Message: err.Error()})
+			}
+		}
+		for _, name := range p.Weekdays {
+			if _, err := models.ParseWeekday(name); err != nil {
+				errs = append(errs, FieldError{Index: i, Field: "weekdays", Message: err.Error()})
+			}
+		}
+		errs = append(errs, v.validateCustom(i, p.TimeEntry, nil)...)
+	}
+	return errs
+}
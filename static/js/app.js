@@ -1,4 +1,14 @@
 document.addEventListener('DOMContentLoaded', function() {
+    // ログインの期限が切れた場合はログイン画面に移動する
+    const originalFetch = window.fetch.bind(window);
+    window.fetch = async function(...args) {
+        const response = await originalFetch(...args);
+        if (response.status === 401) {
+            location.href = '/login?next=' + encodeURIComponent(location.pathname);
+        }
+        return response;
+    };
+
     const defaultTimesStart = [
         '08:30', '09:00', '09:30', '10:00', '10:30', '11:00', '11:30',
         '12:00', '12:30', '13:00', '13:30', '14:00', '14:30', '15:00',
@@ -47,12 +57,38 @@ document.addEventListener('DOMContentLoaded', function() {
     }
 
     function initialize() {
+        setupUserMenu();
         setTodayDate();
         loadDbItems();
         loadEntriesForSelectedDate(dateInput.value, false);
         setupEventListeners();
     }
 
+    // ログインしている場合はユーザー名とログアウトのボタンを表示し、権限に応じて操作を制限する
+    async function setupUserMenu() {
+        const userMenu = document.getElementById('user-menu');
+        try {
+            const response = await fetch('/api/auth/me');
+            const me = await response.json();
+            if (!response.ok || !me.auth_enabled) {
+                return;
+            }
+            document.getElementById('user-name').textContent = me.user;
+            userMenu.style.display = '';
+            // 業務データベースの変更は管理者のみ
+            if (me.role !== 'admin') {
+                dbAddBtn.disabled = true;
+                dbAddBtn.title = '業務データベースの変更には管理者の権限が必要です';
+            }
+            document.getElementById('logout-btn').addEventListener('click', async () => {
+                await fetch('/api/auth/logout', { method: 'POST' });
+                location.href = '/login';
+            });
+        } catch (error) {
+            console.error('Error loading current user:', error);
+        }
+    }
+
     function setupEventListeners() {
         dateInput.addEventListener('change', () => loadEntriesForSelectedDate(dateInput.value, true));
         addRowBtn.addEventListener('click', addEmptyRow);
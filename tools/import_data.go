@@ -1,25 +1,30 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
-	"path/filepath"
 
+	"github.com/yourusername/timeslice-app/internal/config"
 	"github.com/yourusername/timeslice-app/internal/models"
 	"github.com/yourusername/timeslice-app/internal/repository"
 )
 
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Println("使用方法: go run import_data.go <JSONファイルのパス>")
+	// バックエンドなどの設定は cmd/main.go と同じ設定ファイル・環境変数・引数で指定する
+	cfg, err := config.Load("import_data", os.Args[1:])
+	if err != nil {
+		log.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if len(cfg.Args) != 1 {
+		fmt.Println("使用方法: go run ./tools [-backend sqlite -sqlite-path timeslice.db] <JSONファイルのパス>")
 		os.Exit(1)
 	}
 
-	jsonPath := os.Args[1]
-	data, err := ioutil.ReadFile(jsonPath)
+	jsonPath := cfg.Args[0]
+	data, err := os.ReadFile(jsonPath)
 	if err != nil {
 		log.Fatalf("JSONファイルの読み込みに失敗しました: %v", err)
 	}
@@ -32,17 +37,17 @@ func main() {
 		log.Fatalf("JSONのパースに失敗しました: %v", err)
 	}
 
-	// データベースの初期化
-	dbPath := filepath.Join("..", "timeslice.db")
-	repo, err := repository.NewRepository(dbPath)
+	// リポジトリの初期化
+	ctx := context.Background()
+	repo, err := repository.NewRepository(ctx, cfg)
 	if err != nil {
-		log.Fatalf("データベースの初期化に失敗しました: %v", err)
+		log.Fatalf("リポジトリの初期化に失敗しました: %v", err)
 	}
 
 	// DbItemsのインポート
-	if err := repo.SaveDbItems(importData.DbItems); err != nil {
+	if err := repo.SaveDbItems(ctx, importData.DbItems); err != nil {
 		log.Fatalf("DbItemsの保存に失敗しました: %v", err)
 	}
 
-	fmt.Println("データのインポートが完了しました")
+	fmt.Printf("データのインポートが完了しました (backend=%s, %d件)\n", cfg.Backend, len(importData.DbItems))
 }